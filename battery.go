@@ -0,0 +1,20 @@
+//go:build tinygo
+
+package main
+
+import (
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+// BATTERY_DIVIDER is the pack-to-pin ratio of the battery divider (20k over 10k), which keeps a full 2S pack
+// under both boards' ADC reference.
+const BATTERY_DIVIDER = 3
+
+// Battery reads the pack voltage on an ADC input behind the BATTERY_DIVIDER resistor divider.
+type Battery struct {
+	adc interface{ Get() uint16 }
+}
+
+func (b Battery) Millivolts() uint16 {
+	return navlogic.AdcToMillivolts(b.adc.Get(), ADC_REF_MV, BATTERY_DIVIDER)
+}
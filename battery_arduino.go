@@ -0,0 +1,26 @@
+//go:build tinygo && !bluepill
+
+package main
+
+import (
+	"device/avr"
+)
+
+// ADC_REF_MV is the ADC reference: AVcc, the regulated 5 V rail.
+const ADC_REF_MV = 5000
+
+// AnalogChannel is one of the ADC-only inputs A6 and A7 of the Nano and Pro Mini, which have no
+// machine.Pin for machine.ADC to use.
+type AnalogChannel uint8
+
+// Get converts the channel against AVcc and scales the result to 16 bits like machine.ADC;
+// machine.InitADC has already enabled the ADC.
+func (c AnalogChannel) Get() uint16 {
+	avr.ADMUX.Set(avr.ADMUX_REFS0 | uint8(c))
+	avr.ADCSRA.SetBits(avr.ADCSRA_ADSC)
+	for avr.ADCSRA.HasBits(avr.ADCSRA_ADSC) {
+	}
+	low := avr.ADCL.Get() // ADCL first: reading it latches ADCH
+	high := avr.ADCH.Get()
+	return (uint16(high)<<8 | uint16(low)) << 6
+}
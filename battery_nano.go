@@ -0,0 +1,6 @@
+//go:build tinygo && !bluepill && !arduino
+
+package main
+
+// BATTERY_FITTED enables the battery monitor on A6 (Nano, Pro Mini); set it to false if the divider is not wired.
+const BATTERY_FITTED = true
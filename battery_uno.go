@@ -0,0 +1,6 @@
+//go:build tinygo && arduino
+
+package main
+
+// BATTERY_FITTED is false on the Uno: it has no A6, so there is no battery divider to read.
+const BATTERY_FITTED = false
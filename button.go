@@ -0,0 +1,21 @@
+//go:build tinygo
+
+package main
+
+import (
+	"machine"
+)
+
+// Button is a push button from pin to GND, read through the internal pull-up.
+type Button struct {
+	pin machine.Pin
+}
+
+func NewButton(pin machine.Pin) Button {
+	pin.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+	return Button{pin: pin}
+}
+
+func (b Button) Pressed() bool {
+	return !b.pin.Get()
+}
@@ -0,0 +1,44 @@
+//go:build tinygo && !bluepill
+
+package main
+
+import (
+	"device/avr"
+	"machine"
+)
+
+// timer2Prescalers are the Timer2 clock dividers, in the order of their CS2 bit values 1..7.
+var timer2Prescalers = [7]uint32{1, 8, 32, 64, 128, 256, 1024}
+
+// Buzzer drives a piezo on OC2A (D11): Timer2 in CTC mode toggles the pin in hardware,
+// so a tone costs no CPU time. Timer1 is taken by the motors.
+type Buzzer struct {
+	pin machine.Pin
+}
+
+func NewBuzzer(pin machine.Pin) *Buzzer {
+	pin.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	b := &Buzzer{pin: pin}
+	b.Tone(0)
+	return b
+}
+
+// Tone picks the smallest prescaler whose compare value fits 8 bits; pitches below ~31 Hz are silent.
+func (b *Buzzer) Tone(hz uint16) {
+	avr.TCCR2B.Set(0)
+	avr.TCCR2A.Set(0)
+	b.pin.Low()
+	if hz == 0 {
+		return
+	}
+	for i, prescale := range timer2Prescalers {
+		top := machine.CPUFrequency() / (2 * prescale * uint32(hz))
+		if top >= 1 && top <= 256 {
+			avr.TCNT2.Set(0)
+			avr.OCR2A.Set(uint8(top - 1))
+			avr.TCCR2A.Set(avr.TCCR2A_COM2A0 | avr.TCCR2A_WGM21)
+			avr.TCCR2B.Set(uint8(i + 1))
+			return
+		}
+	}
+}
@@ -0,0 +1,32 @@
+//go:build tinygo && bluepill
+
+package main
+
+import (
+	"machine"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/pet"
+)
+
+// Buzzer drives a piezo from a timer PWM channel at 50% duty; the timer period sets the pitch.
+type Buzzer struct {
+	pwm     *machine.TIM
+	channel uint8
+}
+
+func NewBuzzer(pwm *machine.TIM, pin machine.Pin) *Buzzer {
+	pwm.Configure(machine.PWMConfig{Period: 1000000000 / pet.BEEP_HZ})
+	b := &Buzzer{pwm: pwm}
+	b.channel, _ = pwm.Channel(pin)
+	b.Tone(0)
+	return b
+}
+
+func (b *Buzzer) Tone(hz uint16) {
+	if hz == 0 {
+		b.pwm.Set(b.channel, 0)
+		return
+	}
+	b.pwm.SetPeriod(1000000000 / uint64(hz))
+	b.pwm.Set(b.channel, b.pwm.Top()/2)
+}
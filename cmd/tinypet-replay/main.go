@@ -0,0 +1,172 @@
+// Command tinypet-replay feeds a recorded sensor trace through the pet's navigation and prints what it did.
+//
+// The input is a trace file (see pet.ReadTrace for the format) or, with -telemetry, a captured telemetry
+// stream whose sensor readings become the trace. Each tick runs the same pet.Pet.Tick as the firmware, and
+// every change of navigation state or wheel speeds is printed; for a telemetry capture the recorded state
+// and wheel speeds are shown alongside, marked with * where the replay differs. The trace's expect lines
+// are checked at the end (exit status 1 on a mismatch).
+//
+//	go run ./cmd/tinypet-replay internal/navlogic/testdata/edge_head_on.trace
+//	go run ./cmd/tinypet-replay -telemetry -save desk.trace run.bin
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/console"
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+	"github.com/GyeongHoKim/tiny-pet/internal/pet"
+)
+
+func main() {
+	telemetry := flag.Bool("telemetry", false, "the input is a telemetry capture, not a trace")
+	save := flag.String("save", "", "write the trace to this file, e.g. to turn a capture into a regression test")
+	obstacle := flag.Int("obstacle", 0, "obstacle threshold in cm (default: the trace's)")
+	edge := flag.String("edge", "", "IR edge thresholds t0,t1,t2,t3 (default: the trace's)")
+	verbose := flag.Bool("v", false, "print every tick, not only changes")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: tinypet-replay [flags] [trace or capture file]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	in := io.Reader(os.Stdin)
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fail("%v", err)
+		}
+		defer f.Close()
+		in = f
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var trace *pet.Trace
+	var recorded []navlogic.TelemetrySample
+	if *telemetry {
+		var err error
+		if recorded, err = readTelemetry(in); err != nil {
+			fail("%v", err)
+		}
+		trace = pet.TraceFromTelemetry(recorded)
+	} else {
+		var err error
+		if trace, err = pet.ReadTrace(in); err != nil {
+			fail("%v", err)
+		}
+	}
+	if *obstacle > 0 {
+		trace.ObstacleCm = *obstacle
+	}
+	if *edge != "" {
+		thresholds, err := parseThresholds(*edge)
+		if err != nil {
+			fail("edge %q: %v", *edge, err)
+		}
+		trace.EdgeThresholds = thresholds
+	}
+	if *save != "" {
+		if err := saveTrace(*save, trace); err != nil {
+			fail("%v", err)
+		}
+	}
+
+	steps := pet.Replay(trace)
+	printTimeline(os.Stdout, steps, recorded, *verbose)
+	if err := trace.Check(steps); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if len(trace.Expects) > 0 {
+		fmt.Printf("%d expectations met\n", len(trace.Expects))
+	}
+}
+
+// printTimeline prints the steps where the state or wheel speeds change (all of them if verbose), with
+// the recorded state and speeds when the trace came from telemetry.
+func printTimeline(w io.Writer, steps []pet.ReplayStep, recorded []navlogic.TelemetrySample, verbose bool) {
+	var last pet.ReplayStep
+	for i, s := range steps {
+		changed := i == 0 || s.State != last.State || s.Left != last.Left || s.Right != last.Right
+		last = s
+		if !changed && !verbose {
+			continue
+		}
+		fmt.Fprintf(w, "%8d ms  %-11s L=%4d R=%4d", s.TimeMs, console.StateNames[s.State], s.Left, s.Right)
+		if i < len(recorded) {
+			r := recorded[i]
+			mark := ""
+			if int(r.State) != s.State || int(r.LeftMotor) != s.Left || int(r.RightMotor) != s.Right {
+				mark = " *"
+			}
+			fmt.Fprintf(w, "   recorded %-11s L=%4d R=%4d%s", stateName(r.State), r.LeftMotor, r.RightMotor, mark)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func stateName(state uint8) string {
+	if int(state) < len(console.StateNames) {
+		return console.StateNames[state]
+	}
+	return strconv.Itoa(int(state))
+}
+
+// readTelemetry decodes every frame in a capture, skipping console text and corrupt frames.
+func readTelemetry(r io.Reader) ([]navlogic.TelemetrySample, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var samples []navlogic.TelemetrySample
+	for {
+		s, used, _, ok := navlogic.NextTelemetryFrame(data)
+		data = data[used:]
+		if !ok {
+			return samples, nil
+		}
+		samples = append(samples, s)
+	}
+}
+
+func parseThresholds(s string) ([pet.IR_SENSOR_COUNT]uint16, error) {
+	var thresholds [pet.IR_SENSOR_COUNT]uint16
+	parts := strings.Split(s, ",")
+	if len(parts) != len(thresholds) {
+		return thresholds, fmt.Errorf("want %d values, got %d", len(thresholds), len(parts))
+	}
+	for i, p := range parts {
+		v, err := strconv.ParseUint(strings.TrimSpace(p), 10, 16)
+		if err != nil {
+			return thresholds, err
+		}
+		thresholds[i] = uint16(v)
+	}
+	return thresholds, nil
+}
+
+func saveTrace(path string, trace *pet.Trace) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := trace.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func fail(format string, a ...interface{}) {
+	fmt.Fprintf(os.Stderr, "tinypet-replay: "+format+"\n", a...)
+	os.Exit(2)
+}
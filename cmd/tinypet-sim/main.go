@@ -0,0 +1,233 @@
+// Command tinypet-sim runs the pet firmware logic against a simulated desk on the host.
+//
+// The robot's motors drive differential-drive kinematics, the front IR sensors
+// see the desk surface or nothing past its edges, and the ultrasonic sensor is
+// ray-cast against box obstacles. Each tick runs the same pet.Pet.Tick as the
+// firmware main loop.
+//
+//	go run ./cmd/tinypet-sim -desk 60x40 -box 35,15,8,8 -png frames
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+	"github.com/GyeongHoKim/tiny-pet/internal/pet"
+)
+
+var stateNames = map[int]string{
+	navlogic.StateIdle:              "IDLE",
+	navlogic.StateMoving:            "MOVING",
+	navlogic.StateObstacleAvoidance: "OBSTACLE_AVOIDANCE",
+	navlogic.StateEdgeAvoidance:     "EDGE_AVOIDANCE",
+	navlogic.StateInteracting:       "INTERACTING",
+	navlogic.StateGuarding:          "GUARDING",
+	navlogic.StateAlert:             "ALERT",
+}
+
+var exprNames = map[int]string{
+	pet.EXPR_NEUTRAL:     "neutral",
+	pet.EXPR_HAPPY:       "happy",
+	pet.EXPR_SURPRISED:   "surprised",
+	pet.EXPR_SCARED:      "scared",
+	pet.EXPR_EXCITED:     "excited",
+	pet.EXPR_BLINK:       "blink",
+	pet.EXPR_SICK:        "sick",
+	pet.EXPR_SLEEPY:      "sleepy",
+	pet.EXPR_SLEEPING:    "sleeping",
+	pet.EXPR_LOW_BATTERY: "low-battery",
+}
+
+type boxList []Box
+
+func (b *boxList) String() string { return fmt.Sprint(*b) }
+
+func (b *boxList) Set(s string) error {
+	v, err := parseFloats(s, ",", 4)
+	if err != nil {
+		return fmt.Errorf("box %q: want x,y,w,h: %w", s, err)
+	}
+	*b = append(*b, Box{X: v[0], Y: v[1], W: v[2], H: v[3]})
+	return nil
+}
+
+func parseFloats(s, sep string, n int) ([]float64, error) {
+	parts := strings.Split(s, sep)
+	if len(parts) != n {
+		return nil, fmt.Errorf("want %d values, got %d", n, len(parts))
+	}
+	v := make([]float64, n)
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, err
+		}
+		v[i] = f
+	}
+	return v, nil
+}
+
+func main() {
+	var boxes boxList
+	desk := flag.String("desk", "60x40", "desk size WxH in cm")
+	flag.Var(&boxes, "box", "box obstacle x,y,w,h in cm (repeatable; default one box at 35,15,8,8)")
+	start := flag.String("start", "10,20,0", "start pose x,y,heading (cm, cm, degrees)")
+	ticks := flag.Int("ticks", 600, "main-loop ticks to simulate")
+	tickMs := flag.Float64("tick-ms", 100, "main-loop period in ms")
+	speed := flag.Float64("speed", 10, "wheel speed at full drive in cm/s")
+	wheelBase := flag.Float64("wheelbase", 8, "distance between wheels in cm")
+	rearIR := flag.Bool("rear-ir", true, "fit the optional rear IR edge sensors")
+	weakness := flag.Float64("right-weakness", 0, "percent the right motor runs slower than commanded")
+	trim := flag.String("trim", "0,0", "left,right motor trim in percent (see navlogic.AdjustTrim)")
+	sonarFault := flag.Float64("sonar-fault", 0, "seconds after which the sonar stops echoing (0 = never)")
+	sleepAfter := flag.Float64("sleep-after", navlogic.SleepAfterMs/1000, "seconds of wandering before the pet falls asleep (0 = never)")
+	batteryDrain := flag.Float64("battery-drain", 0, "mV per second a full 1S pack runs down (0 = no battery monitor)")
+	glitch := flag.Float64("sonar-glitch", 0, "percent of pings that return a spurious close echo")
+	threshold := flag.Int("threshold", pet.OBSTACLE_DISTANCE_THRESHOLD, "OBSTACLE_DISTANCE_THRESHOLD in cm")
+	obstacleReverse := flag.Uint("obstacle-reverse", pet.OBSTACLE_REVERSE_MS, "obstacle avoidance reversing arc in ms")
+	obstacleTurn := flag.Uint("obstacle-turn", pet.OBSTACLE_TURN_MS, "obstacle avoidance turning arc in ms")
+	edgeReverse := flag.Uint("edge-reverse", pet.EDGE_REVERSE_MS, "edge avoidance reversing arc in ms")
+	edgeTurn := flag.Uint("edge-turn", pet.EDGE_TURN_MS, "edge avoidance turning arc in ms")
+	edgeHeadOn := flag.Uint("edge-head-on-reverse", pet.EDGE_HEAD_ON_REVERSE_MS, "straight reverse in ms when both front IR sensors see the edge")
+	edgeTurnAround := flag.Uint("edge-turn-around", pet.EDGE_TURN_AROUND_MS, "in-place 180° turn in ms after a head-on edge")
+	pngDir := flag.String("png", "", "write PNG frames to this directory")
+	every := flag.Int("every", 5, "write a PNG frame every N ticks")
+	scale := flag.Float64("scale", 8, "PNG pixels per cm")
+	oled := flag.String("oled", "128x32", "OLED panel size WxH in pixels (128x32, 128x64 or 64x48)")
+	rotate := flag.Int("rotate", 0, "OLED mounting, degrees clockwise (0, 90, 180 or 270)")
+	faces := flag.Bool("faces", false, "print the OLED face as ASCII art when it changes")
+	verbose := flag.Bool("v", false, "log every tick, not only state changes")
+	telemetry := flag.String("telemetry", "", "write the telemetry stream to this file (decode with tinypet-telemetry)")
+	flag.Parse()
+
+	size, err := parseFloats(*desk, "x", 2)
+	if err != nil {
+		fail("desk %q: %v", *desk, err)
+	}
+	pose, err := parseFloats(*start, ",", 3)
+	if err != nil {
+		fail("start %q: %v", *start, err)
+	}
+	trims, err := parseFloats(*trim, ",", 2)
+	if err != nil {
+		fail("trim %q: %v", *trim, err)
+	}
+	panel, err := parseFloats(*oled, "x", 2)
+	if err != nil || panel[0] < 1 || panel[0] > 128 || panel[1] < 1 || panel[1] > 64 {
+		fail("oled %q: want WxH up to 128x64", *oled)
+	}
+	if *rotate%90 != 0 || *rotate < 0 || *rotate > 270 {
+		fail("rotate %d: want 0, 90, 180 or 270", *rotate)
+	}
+	if len(boxes) == 0 {
+		boxes = boxList{{X: 35, Y: 15, W: 8, H: 8}}
+	}
+	if *pngDir != "" {
+		if err := os.MkdirAll(*pngDir, 0o755); err != nil {
+			fail("%v", err)
+		}
+	}
+
+	world := NewWorld(size[0], size[1], boxes)
+	world.X, world.Y, world.Heading = pose[0], pose[1], pose[2]*math.Pi/180
+	world.WheelSpeed = *speed
+	world.WheelBase = *wheelBase
+	world.RearIR = *rearIR
+	world.RightWeakness = *weakness
+	world.SonarGlitch = *glitch
+	world.SonarFaultAt = *sonarFault
+	world.BatteryDrain = *batteryDrain
+
+	geometry := pet.DisplayGeometry{Width: int16(panel[0]), Height: int16(panel[1]), Rotation: *rotate / 90}
+	display := pet.NewFakeRenderer(geometry)
+	robot := world.Robot(display)
+	robot.DisplayGeometry = geometry
+	if *telemetry != "" {
+		f, err := os.Create(*telemetry)
+		if err != nil {
+			fail("%v", err)
+		}
+		defer f.Close()
+		robot.Serial = &captureSerial{w: f}
+	}
+	p := pet.New(robot)
+	p.SetTelemetry(*telemetry != "")
+	p.SetSleepAfter(uint32(*sleepAfter * 1000))
+	p.Sensors().SetObstacleThreshold(*threshold)
+	p.Motors().SetTrim(int(trims[0]), int(trims[1]))
+	p.Navigation().SetAvoidanceTiming(pet.AvoidanceTiming{
+		ObstacleReverseMs:   uint32(*obstacleReverse),
+		ObstacleTurnMs:      uint32(*obstacleTurn),
+		EdgeReverseMs:       uint32(*edgeReverse),
+		EdgeTurnMs:          uint32(*edgeTurn),
+		EdgeHeadOnReverseMs: uint32(*edgeHeadOn),
+		EdgeTurnAroundMs:    uint32(*edgeTurnAround),
+	})
+
+	lastState, lastExpr := -1, -1
+	counts := map[int]int{}
+	for tick := 0; tick < *ticks && !world.Fell; tick++ {
+		p.Tick()
+		state := p.Navigation().GetCurrentState()
+		expr := p.Display().GetCurrentExpression()
+
+		if state != lastState {
+			counts[state]++
+		}
+		if *verbose || state != lastState {
+			fmt.Printf("%7.2fs  x=%5.1f y=%5.1f hdg=%4.0f°  %-18s dist=%4d face=%s\n",
+				world.Time, world.X, world.Y, degrees(world.Heading),
+				stateNames[state], p.Sensors().LastDistance(), exprNames[expr])
+		}
+		if *faces && expr != lastExpr && expr != pet.EXPR_BLINK {
+			fmt.Print(faceASCII(display))
+		}
+		if *pngDir != "" && tick%*every == 0 {
+			path := filepath.Join(*pngDir, fmt.Sprintf("frame_%05d.png", tick))
+			if err := writePNG(path, renderFrame(world, display, *scale)); err != nil {
+				fail("%v", err)
+			}
+		}
+		lastState, lastExpr = state, expr
+
+		world.Advance(*tickMs / 1000)
+	}
+
+	fmt.Printf("\n%.1fs simulated: %d obstacle avoidances, %d edge avoidances, %d collisions\n",
+		world.Time, counts[navlogic.StateObstacleAvoidance], counts[navlogic.StateEdgeAvoidance], world.Collisions)
+	if world.Fell {
+		fmt.Printf("FELL off the desk at x=%.1f y=%.1f\n", world.X, world.Y)
+		os.Exit(1)
+	}
+}
+
+// captureSerial is a serial port with nothing to read that writes to a capture file.
+type captureSerial struct {
+	w io.Writer
+}
+
+func (s *captureSerial) Buffered() int { return 0 }
+
+func (s *captureSerial) ReadByte() (byte, error) { return 0, io.EOF }
+
+func (s *captureSerial) Write(p []byte) (int, error) { return s.w.Write(p) }
+
+func degrees(rad float64) float64 {
+	d := math.Mod(rad*180/math.Pi, 360)
+	if d < 0 {
+		d += 360
+	}
+	return d
+}
+
+func fail(format string, a ...interface{}) {
+	fmt.Fprintf(os.Stderr, "tinypet-sim: "+format+"\n", a...)
+	os.Exit(2)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/pet"
+)
+
+const (
+	oledScale  = 2
+	frameInset = 10
+)
+
+var (
+	colorFloor   = color.RGBA{40, 40, 48, 255}
+	colorDesk    = color.RGBA{196, 164, 120, 255}
+	colorBox     = color.RGBA{90, 90, 90, 255}
+	colorRobot   = color.RGBA{60, 120, 220, 255}
+	colorHeading = color.RGBA{255, 255, 255, 255}
+	colorIROn    = color.RGBA{40, 200, 40, 255}
+	colorIROff   = color.RGBA{230, 40, 40, 255}
+	colorOLED    = color.RGBA{0, 0, 0, 255}
+	colorPixel   = color.RGBA{120, 220, 255, 255}
+)
+
+// faceASCII renders the OLED frame at half resolution, one character per 2x2 pixel block.
+func faceASCII(display *pet.FakeRenderer) string {
+	var sb strings.Builder
+	oledWidth, oledHeight := display.Geometry.FaceSize()
+	for y := int16(0); y < oledHeight; y += 2 {
+		for x := int16(0); x < oledWidth; x += 2 {
+			if display.FacePixel(x, y) || display.FacePixel(x+1, y) || display.FacePixel(x, y+1) || display.FacePixel(x+1, y+1) {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// renderFrame draws the desk top-down at scale px/cm with the OLED face below it.
+func renderFrame(w *World, display *pet.FakeRenderer, scale float64) *image.RGBA {
+	faceW, faceH := display.Geometry.FaceSize()
+	oledWidth, oledHeight := int(faceW), int(faceH)
+	deskW := int(w.DeskW*scale) + 2*frameInset
+	width := max(deskW, oledWidth*oledScale+2*frameInset)
+	deskH := int(w.DeskH*scale) + 2*frameInset
+	height := deskH + oledHeight*oledScale + frameInset
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	fillRect(img, 0, 0, width, height, colorFloor)
+
+	toPx := func(x, y float64) (int, int) {
+		return frameInset + int(x*scale), frameInset + int((w.DeskH-y)*scale)
+	}
+
+	x0, y0 := toPx(0, w.DeskH)
+	fillRect(img, x0, y0, int(w.DeskW*scale), int(w.DeskH*scale), colorDesk)
+	for _, b := range w.Boxes {
+		bx, by := toPx(b.X, b.Y+b.H)
+		fillRect(img, bx, by, int(b.W*scale), int(b.H*scale), colorBox)
+	}
+
+	rx, ry := toPx(w.X, w.Y)
+	fillCircle(img, rx, ry, int(robotRadius*scale), colorRobot)
+	hx, hy := toPx(w.bodyPoint(robotRadius, 0))
+	drawLine(img, rx, ry, hx, hy, colorHeading)
+	for i, m := range irMounts {
+		if !w.RearIR && (i == pet.IR_REAR_LEFT || i == pet.IR_REAR_RIGHT) {
+			continue
+		}
+		sx, sy := w.bodyPoint(m.forward, m.lateral)
+		c := colorIROn
+		if !w.onDesk(sx, sy) {
+			c = colorIROff
+		}
+		px, py := toPx(sx, sy)
+		fillCircle(img, px, py, int(math.Max(1, scale/2)), c)
+	}
+
+	ox, oy := frameInset, deskH
+	fillRect(img, ox, oy, oledWidth*oledScale, oledHeight*oledScale, colorOLED)
+	for y := int16(0); y < faceH; y++ {
+		for x := int16(0); x < faceW; x++ {
+			if display.FacePixel(x, y) {
+				fillRect(img, ox+int(x)*oledScale, oy+int(y)*oledScale, oledScale, oledScale, colorPixel)
+			}
+		}
+	}
+	return img
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func fillRect(img *image.RGBA, x, y, w, h int, c color.RGBA) {
+	for dy := 0; dy < h; dy++ {
+		for dx := 0; dx < w; dx++ {
+			img.SetRGBA(x+dx, y+dy, c)
+		}
+	}
+}
+
+func fillCircle(img *image.RGBA, cx, cy, r int, c color.RGBA) {
+	for dy := -r; dy <= r; dy++ {
+		for dx := -r; dx <= r; dx++ {
+			if dx*dx+dy*dy <= r*r {
+				img.SetRGBA(cx+dx, cy+dy, c)
+			}
+		}
+	}
+}
+
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	steps := max(abs(x1-x0), abs(y1-y0), 1)
+	for i := 0; i <= steps; i++ {
+		img.SetRGBA(x0+(x1-x0)*i/steps, y0+(y1-y0)*i/steps, c)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+	"github.com/GyeongHoKim/tiny-pet/internal/pet"
+)
+
+const (
+	robotRadius      = 5.0
+	irForwardOffset  = 5.0
+	irLateralOffset  = 3.0
+	sonarOffset      = 5.0
+	sonarMaxRange    = 400.0
+	sonarHalfBeamDeg = 15.0
+	sonarRays        = 5
+	surfaceReading   = 40000
+	integrationStep  = 0.005
+)
+
+// Box is an axis-aligned obstacle on the desk; X, Y is its lower-left corner in cm.
+type Box struct {
+	X, Y, W, H float64
+}
+
+func (b Box) contains(x, y, margin float64) bool {
+	return x > b.X-margin && x < b.X+b.W+margin && y > b.Y-margin && y < b.Y+b.H+margin
+}
+
+// World is a rectangular desk top with box obstacles and a differential-drive robot on it.
+type World struct {
+	DeskW, DeskH float64
+	Boxes        []Box
+
+	X, Y       float64
+	Heading    float64
+	WheelSpeed float64
+	WheelBase  float64
+	RearIR     bool
+	// RightWeakness is how much slower the right motor runs than commanded, in percent.
+	RightWeakness float64
+	// SonarGlitch is the chance in percent that a ping returns a spurious close echo.
+	SonarGlitch float64
+	// SonarFaultAt is the simulated time in s after which the sonar stops echoing; 0 keeps it working.
+	SonarFaultAt float64
+	// BatteryDrain is how fast the 1S pack, full at the start, loses charge in mV per simulated s; 0 fits no battery monitor.
+	BatteryDrain float64
+
+	Time       float64
+	Fell       bool
+	Collisions int
+	blocked    bool
+
+	left  simMotor
+	right simMotor
+	rng   *rand.Rand
+}
+
+func NewWorld(deskW, deskH float64, boxes []Box) *World {
+	return &World{
+		DeskW:      deskW,
+		DeskH:      deskH,
+		Boxes:      boxes,
+		WheelSpeed: 10,
+		WheelBase:  8,
+		RearIR:     true,
+		rng:        rand.New(rand.NewSource(1)),
+	}
+}
+
+// Robot returns a pet.Robot whose motors and sensors are backed by the world.
+func (w *World) Robot(display pet.FaceRenderer) *pet.Robot {
+	robot := &pet.Robot{
+		LeftMotor:  &w.left,
+		RightMotor: &w.right,
+		Ultrasonic: &sonar{world: w},
+		StatusLed:  &pet.FakeIndicator{},
+		Buzzer:     &pet.FakeBuzzer{},
+		Display:    display,
+		Clock:      &simClock{world: w},
+		Power:      &pet.FakePower{},
+	}
+	if w.BatteryDrain > 0 {
+		robot.Battery = &simBattery{world: w}
+		robot.BatteryPack = navlogic.PackLiPo1S
+	}
+	for i, m := range irMounts {
+		if !w.RearIR && (i == pet.IR_REAR_LEFT || i == pet.IR_REAR_RIGHT) {
+			continue
+		}
+		robot.IRSensors[i] = &irSensor{world: w, mount: m}
+	}
+	return robot
+}
+
+// simClock reads the world's simulated time.
+type simClock struct {
+	world *World
+}
+
+func (c *simClock) Millis() uint32 {
+	return uint32(math.Round(c.world.Time * 1000))
+}
+
+const (
+	simBatteryFullMv  = 4200
+	simBatteryEmptyMv = 3000
+)
+
+// simBattery is a 1S pack that runs down by BatteryDrain mV every simulated second.
+type simBattery struct {
+	world *World
+}
+
+func (b *simBattery) Millivolts() uint16 {
+	return uint16(math.Max(simBatteryFullMv-b.world.BatteryDrain*b.world.Time, simBatteryEmptyMv))
+}
+
+// Advance integrates the robot pose for dt seconds of motor time.
+func (w *World) Advance(dt float64) {
+	for dt > 0 && !w.Fell {
+		step := math.Min(dt, integrationStep)
+		dt -= step
+		w.Time += step
+
+		vl := float64(w.left.speed) / pet.MAX_SPEED * w.WheelSpeed
+		vr := float64(w.right.speed) / pet.MAX_SPEED * w.WheelSpeed * (1 - w.RightWeakness/100)
+		v := (vl + vr) / 2
+		omega := (vr - vl) / w.WheelBase
+
+		w.Heading = math.Mod(w.Heading+omega*step, 2*math.Pi)
+		nx := w.X + v*math.Cos(w.Heading)*step
+		ny := w.Y + v*math.Sin(w.Heading)*step
+		if w.collides(nx, ny) {
+			if !w.blocked {
+				w.Collisions++
+			}
+			w.blocked = true
+			continue
+		}
+		w.blocked = false
+		w.X, w.Y = nx, ny
+		if !w.onDesk(w.X, w.Y) {
+			w.Fell = true
+		}
+	}
+}
+
+func (w *World) collides(x, y float64) bool {
+	for _, b := range w.Boxes {
+		if b.contains(x, y, robotRadius) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *World) onDesk(x, y float64) bool {
+	return x >= 0 && x <= w.DeskW && y >= 0 && y <= w.DeskH
+}
+
+// bodyPoint returns the desk position of a point given in robot coordinates (forward, left).
+func (w *World) bodyPoint(forward, lateral float64) (float64, float64) {
+	c, s := math.Cos(w.Heading), math.Sin(w.Heading)
+	return w.X + forward*c - lateral*s, w.Y + forward*s + lateral*c
+}
+
+// rangeTo returns the distance along the ray from (x, y) at angle to the nearest box, or +Inf.
+func (w *World) rangeTo(x, y, angle float64) float64 {
+	dx, dy := math.Cos(angle), math.Sin(angle)
+	nearest := math.Inf(1)
+	for _, b := range w.Boxes {
+		if d, ok := rayBox(x, y, dx, dy, b); ok && d < nearest {
+			nearest = d
+		}
+	}
+	return nearest
+}
+
+// rayBox intersects a ray with a box using the slab method.
+func rayBox(x, y, dx, dy float64, b Box) (float64, bool) {
+	tMin, tMax := 0.0, math.Inf(1)
+	for _, axis := range [2][4]float64{{x, dx, b.X, b.X + b.W}, {y, dy, b.Y, b.Y + b.H}} {
+		origin, dir, lo, hi := axis[0], axis[1], axis[2], axis[3]
+		if dir == 0 {
+			if origin < lo || origin > hi {
+				return 0, false
+			}
+			continue
+		}
+		t1, t2 := (lo-origin)/dir, (hi-origin)/dir
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tMin, tMax = math.Max(tMin, t1), math.Min(tMax, t2)
+		if tMin > tMax {
+			return 0, false
+		}
+	}
+	return tMin, true
+}
+
+// simMotor records the speed command; World.Advance turns it into wheel velocity.
+type simMotor struct {
+	speed int
+}
+
+func (m *simMotor) SetSpeed(speed int) { m.speed = speed }
+
+// sonar casts rays across the HC-SR04 beam and reports the nearest hit.
+type sonar struct {
+	world *World
+}
+
+func (s *sonar) ReadRange() navlogic.RangeReading {
+	w := s.world
+	if w.SonarFaultAt > 0 && w.Time >= w.SonarFaultAt {
+		return navlogic.RangeReading{Cm: navlogic.TimeoutDistance, Status: navlogic.RangeNoEcho}
+	}
+	if w.rng.Float64()*100 < w.SonarGlitch {
+		return navlogic.RangeFromCm(2 + w.rng.Intn(10))
+	}
+	x, y := w.bodyPoint(sonarOffset, 0)
+	halfBeam := sonarHalfBeamDeg * math.Pi / 180
+	nearest := math.Inf(1)
+	for i := 0; i < sonarRays; i++ {
+		da := -halfBeam + 2*halfBeam*float64(i)/(sonarRays-1)
+		nearest = math.Min(nearest, w.rangeTo(x, y, w.Heading+da))
+	}
+	if nearest > sonarMaxRange {
+		return navlogic.RangeFromCm(navlogic.TimeoutDistance)
+	}
+	return navlogic.RangeFromCm(int(nearest))
+}
+
+// irMount is an IR sensor position in the robot frame (cm ahead of and left of the centre).
+type irMount struct {
+	forward, lateral float64
+}
+
+var irMounts = [pet.IR_SENSOR_COUNT]irMount{
+	pet.IR_FRONT_LEFT:  {irForwardOffset, irLateralOffset},
+	pet.IR_FRONT_RIGHT: {irForwardOffset, -irLateralOffset},
+	pet.IR_REAR_LEFT:   {-irForwardOffset, irLateralOffset},
+	pet.IR_REAR_RIGHT:  {-irForwardOffset, -irLateralOffset},
+}
+
+// irSensor reads the surface under its mount point; off the desk it sees nothing.
+type irSensor struct {
+	world *World
+	mount irMount
+}
+
+func (s *irSensor) Get() uint16 {
+	x, y := s.world.bodyPoint(s.mount.forward, s.mount.lateral)
+	if s.world.onDesk(x, y) {
+		return surfaceReading
+	}
+	return 0
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+	"github.com/GyeongHoKim/tiny-pet/internal/pet"
+)
+
+func TestRayBox(t *testing.T) {
+	box := Box{X: 10, Y: -5, W: 4, H: 10}
+	tests := []struct {
+		name   string
+		dx, dy float64
+		want   float64
+		hit    bool
+	}{
+		{"straight at box", 1, 0, 10, true},
+		{"away from box", -1, 0, 0, false},
+		{"parallel beside box", 0, 1, 0, false},
+		{"diagonal passes over box", math.Sqrt2 / 2, math.Sqrt2 / 2, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, hit := rayBox(0, 0, tt.dx, tt.dy, box)
+			if hit != tt.hit || (hit && math.Abs(got-tt.want) > 1e-9) {
+				t.Errorf("rayBox = (%v, %v), want (%v, %v)", got, hit, tt.want, tt.hit)
+			}
+		})
+	}
+}
+
+func TestAdvance_Kinematics(t *testing.T) {
+	tests := []struct {
+		name        string
+		left, right int
+		wantX       float64
+		wantHeading float64
+	}{
+		{"forward 1 s", 100, 100, 20, 0},
+		{"half speed 1 s", 50, 50, 15, 0},
+		{"backward 1 s", -100, -100, 0, 0},
+		{"spin left 1 s", -100, 100, 10, 2.5},
+		{"stopped", 0, 0, 10, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWorld(100, 100, nil)
+			w.X, w.Y = 10, 50
+			w.left.SetSpeed(tt.left)
+			w.right.SetSpeed(tt.right)
+			w.Advance(1)
+			if math.Abs(w.X-tt.wantX) > 1e-6 || math.Abs(w.Heading-tt.wantHeading) > 1e-6 {
+				t.Errorf("pose = (x=%v, heading=%v), want (x=%v, heading=%v)", w.X, w.Heading, tt.wantX, tt.wantHeading)
+			}
+		})
+	}
+}
+
+func TestAdvance_FallsOffDesk(t *testing.T) {
+	w := NewWorld(20, 20, nil)
+	w.X, w.Y = 15, 10
+	w.left.SetSpeed(100)
+	w.right.SetSpeed(100)
+	w.Advance(1)
+	if !w.Fell {
+		t.Fatalf("robot at x=%v did not fall off a 20 cm desk", w.X)
+	}
+}
+
+func TestAdvance_BoxBlocksRobot(t *testing.T) {
+	w := NewWorld(100, 100, []Box{{X: 30, Y: 40, W: 10, H: 20}})
+	w.X, w.Y = 10, 50
+	w.left.SetSpeed(100)
+	w.right.SetSpeed(100)
+	w.Advance(5)
+	if w.X > 30-robotRadius || w.Collisions != 1 {
+		t.Errorf("x = %v, collisions = %d; want stopped before the box with one collision", w.X, w.Collisions)
+	}
+}
+
+func TestSensors(t *testing.T) {
+	w := NewWorld(100, 100, []Box{{X: 50, Y: 40, W: 10, H: 20}})
+	robot := w.Robot(&pet.FakeRenderer{})
+
+	w.X, w.Y = 20, 50
+	if got := robot.Ultrasonic.ReadRange().Distance(); got != 25 {
+		t.Errorf("distance facing box = %d, want 25", got)
+	}
+	w.Heading = math.Pi
+	if got := robot.Ultrasonic.ReadRange(); got.Status != navlogic.RangeOutOfRange {
+		t.Errorf("reading facing away = %+v, want out of range", got)
+	}
+
+	w.X, w.Y, w.Heading = 3, 50, math.Pi
+	wantFacingEdge := [pet.IR_SENSOR_COUNT]uint16{0, 0, surfaceReading, surfaceReading}
+	for i, s := range robot.IRSensors {
+		if got := s.Get(); got != wantFacingEdge[i] {
+			t.Errorf("IR %d facing edge = %d, want %d", i, got, wantFacingEdge[i])
+		}
+	}
+	w.Heading = 0
+	wantBackToEdge := [pet.IR_SENSOR_COUNT]uint16{surfaceReading, surfaceReading, 0, 0}
+	for i, s := range robot.IRSensors {
+		if got := s.Get(); got != wantBackToEdge[i] {
+			t.Errorf("IR %d backing onto edge = %d, want %d", i, got, wantBackToEdge[i])
+		}
+	}
+
+	w.RearIR = false
+	robot = w.Robot(&pet.FakeRenderer{})
+	if robot.IRSensors[pet.IR_REAR_LEFT] != nil || robot.IRSensors[pet.IR_REAR_RIGHT] != nil {
+		t.Error("rear IR sensors fitted with RearIR = false")
+	}
+}
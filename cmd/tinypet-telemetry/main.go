@@ -0,0 +1,216 @@
+// Command tinypet-telemetry decodes a captured telemetry stream into CSV or JSON lines for plotting.
+//
+// Turn the stream on with the console command "telemetry on" (or the simulator's -telemetry flag), capture
+// the serial port to a file, then decode it. Console text between frames and corrupt frames are skipped;
+// a summary of frames, lost frames and skipped bytes goes to stderr.
+//
+//	stty -F /dev/ttyACM0 115200 raw && cat /dev/ttyACM0 > run.bin
+//	go run ./cmd/tinypet-telemetry -format csv run.bin > run.csv
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/console"
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+// record is one decoded frame as written out.
+type record struct {
+	Seq           uint16    `json:"seq"`
+	TimeMs        uint32    `json:"time_ms"`
+	State         string    `json:"state"`
+	Mode          string    `json:"mode"`
+	Manual        bool      `json:"manual"`
+	Asleep        bool      `json:"asleep"`
+	SonarDegraded bool      `json:"sonar_degraded"`
+	SonarFault    bool      `json:"sonar_fault"`
+	Battery       string    `json:"battery"`
+	DistanceCm    int16     `json:"distance_cm"`
+	IR            [4]uint16 `json:"ir"`
+	IRFitted      [4]bool   `json:"ir_fitted"`
+	LeftMotor     int8      `json:"left_motor"`
+	RightMotor    int8      `json:"right_motor"`
+	LoopMs        uint16    `json:"loop_ms"`
+	BusyMs        uint16    `json:"busy_ms"`
+}
+
+var csvHeader = []string{
+	"seq", "time_ms", "state", "mode", "manual", "asleep", "sonar_degraded", "sonar_fault", "battery", "distance_cm",
+	"ir0", "ir1", "ir2", "ir3", "ir_fitted", "left_motor", "right_motor", "loop_ms", "busy_ms",
+}
+
+func newRecord(s navlogic.TelemetrySample) record {
+	battery := "ok"
+	switch {
+	case s.Flags&navlogic.TelemetryFlagBatteryFlat != 0:
+		battery = "flat"
+	case s.Flags&navlogic.TelemetryFlagBatteryLow != 0:
+		battery = "low"
+	}
+	var fitted [4]bool
+	for i := range fitted {
+		fitted[i] = s.IRFitted&(1<<i) != 0
+	}
+	return record{
+		Seq:           s.Seq,
+		TimeMs:        s.TimeMs,
+		State:         name(console.StateNames[:], s.State),
+		Mode:          name(console.ModeNames[:], s.Mode),
+		Manual:        s.Flags&navlogic.TelemetryFlagManual != 0,
+		Asleep:        s.Flags&navlogic.TelemetryFlagAsleep != 0,
+		SonarDegraded: s.Flags&navlogic.TelemetryFlagSonarDegraded != 0,
+		SonarFault:    s.Flags&navlogic.TelemetryFlagSonarFault != 0,
+		Battery:       battery,
+		DistanceCm:    s.Distance,
+		IR:            s.IR,
+		IRFitted:      fitted,
+		LeftMotor:     s.LeftMotor,
+		RightMotor:    s.RightMotor,
+		LoopMs:        s.LoopMs,
+		BusyMs:        s.BusyMs,
+	}
+}
+
+func (r record) csv() []string {
+	row := []string{
+		strconv.Itoa(int(r.Seq)), strconv.FormatUint(uint64(r.TimeMs), 10), r.State, r.Mode,
+		flag01(r.Manual), flag01(r.Asleep), flag01(r.SonarDegraded), flag01(r.SonarFault), r.Battery,
+		strconv.Itoa(int(r.DistanceCm)),
+	}
+	fitted := ""
+	for i, v := range r.IR {
+		row = append(row, strconv.Itoa(int(v)))
+		fitted += flag01(r.IRFitted[i])
+	}
+	return append(row, fitted, strconv.Itoa(int(r.LeftMotor)), strconv.Itoa(int(r.RightMotor)),
+		strconv.Itoa(int(r.LoopMs)), strconv.Itoa(int(r.BusyMs)))
+}
+
+// name returns names[i], or the number when a newer firmware sends a value this tool does not know.
+func name(names []string, i uint8) string {
+	if int(i) < len(names) {
+		return names[i]
+	}
+	return strconv.Itoa(int(i))
+}
+
+func flag01(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// stats summarises a decoded capture.
+type stats struct {
+	Frames  int
+	Lost    int
+	Skipped int
+}
+
+// decode reads a capture from r and hands every frame to emit in order.
+func decode(r io.Reader, emit func(navlogic.TelemetrySample) error) (stats, error) {
+	var st stats
+	var pending []byte
+	buf := make([]byte, 4096)
+	havePrev := false
+	var prev navlogic.TelemetrySample
+	for {
+		n, err := r.Read(buf)
+		pending = append(pending, buf[:n]...)
+		for {
+			s, used, skipped, ok := navlogic.NextTelemetryFrame(pending)
+			pending = pending[used:]
+			st.Skipped += skipped
+			if !ok {
+				break
+			}
+			// Time going backwards means the board restarted its count.
+			if havePrev && s.TimeMs >= prev.TimeMs {
+				st.Lost += navlogic.SeqGap(prev.Seq, s.Seq)
+			}
+			prev, havePrev = s, true
+			st.Frames++
+			if err := emit(s); err != nil {
+				return st, err
+			}
+		}
+		if err == io.EOF {
+			st.Skipped += len(pending)
+			return st, nil
+		}
+		if err != nil {
+			return st, err
+		}
+	}
+}
+
+func main() {
+	format := flag.String("format", "csv", "output format: csv or json (one object per line)")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: tinypet-telemetry [-format csv|json] [capture file]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	in := io.Reader(os.Stdin)
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fail("%v", err)
+		}
+		defer f.Close()
+		in = f
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	var emit func(navlogic.TelemetrySample) error
+	flush := out.Flush
+	switch *format {
+	case "csv":
+		w := csv.NewWriter(out)
+		if err := w.Write(csvHeader); err != nil {
+			fail("%v", err)
+		}
+		emit = func(s navlogic.TelemetrySample) error { return w.Write(newRecord(s).csv()) }
+		flush = func() error {
+			w.Flush()
+			if err := w.Error(); err != nil {
+				return err
+			}
+			return out.Flush()
+		}
+	case "json":
+		enc := json.NewEncoder(out)
+		emit = func(s navlogic.TelemetrySample) error { return enc.Encode(newRecord(s)) }
+	default:
+		fail("unknown format %q", *format)
+	}
+
+	st, err := decode(bufio.NewReader(in), emit)
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		fail("%v", err)
+	}
+	fmt.Fprintf(os.Stderr, "%d frames, %d lost, %d bytes skipped\n", st.Frames, st.Lost, st.Skipped)
+}
+
+func fail(format string, a ...interface{}) {
+	fmt.Fprintf(os.Stderr, "tinypet-telemetry: "+format+"\n", a...)
+	os.Exit(2)
+}
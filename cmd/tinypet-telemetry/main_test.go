@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+func frame(seq uint16, timeMs uint32) []byte {
+	var buf [navlogic.TelemetryFrameSize]byte
+	navlogic.TelemetrySample{Seq: seq, TimeMs: timeMs, State: navlogic.StateMoving}.Encode(&buf)
+	return buf[:]
+}
+
+func TestDecode(t *testing.T) {
+	var capture bytes.Buffer
+	capture.WriteString("Tiny Pet\r\n")
+	capture.Write(frame(7, 100))
+	capture.Write(frame(8, 110))
+	capture.WriteString("ok\r\n")
+	capture.Write(frame(11, 140)) // 9 and 10 lost
+	capture.Write(frame(0, 5))    // board restarted
+	capture.Write(frame(1, 15)[:10])
+
+	var seqs []uint16
+	st, err := decode(iotest.OneByteReader(&capture), func(s navlogic.TelemetrySample) error {
+		seqs = append(seqs, s.Seq)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := stats{Frames: 4, Lost: 2, Skipped: len("Tiny Pet\r\n") + len("ok\r\n") + 10}
+	if st != want {
+		t.Errorf("stats = %+v, want %+v", st, want)
+	}
+	if len(seqs) != 4 || seqs[2] != 11 {
+		t.Errorf("sequence numbers = %v, want [7 8 11 0]", seqs)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	s := navlogic.TelemetrySample{
+		Seq:       3,
+		TimeMs:    1200,
+		State:     navlogic.StateEdgeAvoidance,
+		Mode:      1,
+		Flags:     navlogic.TelemetryFlagManual | navlogic.TelemetryFlagBatteryLow | navlogic.TelemetryFlagSonarFault,
+		Distance:  -1,
+		IR:        [navlogic.TelemetryIRSensors]uint16{1, 2, 3, 0},
+		LeftMotor: -50,
+		LoopMs:    10,
+		BusyMs:    2,
+		IRFitted:  0b0111,
+	}
+	got := strings.Join(newRecord(s).csv(), ",")
+	if want := "3,1200,edge,guard,1,0,0,1,low,-1,1,2,3,0,1110,-50,0,10,2"; got != want {
+		t.Errorf("csv = %s, want %s", got, want)
+	}
+	if got := len(newRecord(s).csv()); got != len(csvHeader) {
+		t.Errorf("%d csv columns, header has %d", got, len(csvHeader))
+	}
+	s.State = 200
+	if got := newRecord(s).State; got != "200" {
+		t.Errorf("unknown state = %q, want the number", got)
+	}
+}
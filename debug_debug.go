@@ -1,7 +0,0 @@
-//go:build debug
-
-package main
-
-func debugPrint(a ...interface{}) {
-	println(a...)
-}
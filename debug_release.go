@@ -1,5 +0,0 @@
-//go:build !debug
-
-package main
-
-func debugPrint(...interface{}) {}
@@ -0,0 +1,9 @@
+//go:build !oled_128x64 && !oled_64x48
+
+package main
+
+// The default panel: a 0.91" 128x32 OLED. Build with -tags=oled_128x64 or oled_64x48 for another size.
+const (
+	DISPLAY_WIDTH  = 128
+	DISPLAY_HEIGHT = 32
+)
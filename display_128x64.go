@@ -0,0 +1,9 @@
+//go:build oled_128x64
+
+package main
+
+// A 0.96" 128x64 OLED. Its frame buffer takes 1 KB, half the SRAM of an Uno/Nano.
+const (
+	DISPLAY_WIDTH  = 128
+	DISPLAY_HEIGHT = 64
+)
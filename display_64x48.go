@@ -0,0 +1,9 @@
+//go:build oled_64x48
+
+package main
+
+// A 0.66" 64x48 OLED, such as the Wemos D1 mini shield.
+const (
+	DISPLAY_WIDTH  = 64
+	DISPLAY_HEIGHT = 48
+)
@@ -0,0 +1,9 @@
+//go:build !oled_rotate90 && !oled_rotate180 && !oled_rotate270
+
+package main
+
+import "github.com/GyeongHoKim/tiny-pet/internal/pet"
+
+// DISPLAY_ROTATION is how far the panel is turned clockwise; build with -tags=oled_rotate90, oled_rotate180
+// or oled_rotate270 for a panel mounted sideways or upside down.
+const DISPLAY_ROTATION = pet.ROTATE_0
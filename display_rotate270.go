@@ -0,0 +1,7 @@
+//go:build oled_rotate270
+
+package main
+
+import "github.com/GyeongHoKim/tiny-pet/internal/pet"
+
+const DISPLAY_ROTATION = pet.ROTATE_270
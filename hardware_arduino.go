@@ -1,126 +1,87 @@
-//go:build !bluepill
+//go:build tinygo && !bluepill
 
 package main
 
 import (
 	"machine"
-	"time"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+	"github.com/GyeongHoKim/tiny-pet/internal/pet"
 )
 
 const (
-	LEFT_MOTOR_IN1     = machine.D5
+	LEFT_MOTOR_IN1     = machine.D10
 	LEFT_MOTOR_IN2     = machine.D4
-	RIGHT_MOTOR_IN1    = machine.D6
-	RIGHT_MOTOR_IN2    = machine.D9
+	RIGHT_MOTOR_IN1    = machine.D9
+	RIGHT_MOTOR_IN2    = machine.D6
 	ULTRA_TRIG_PIN     = machine.D7
-	ULTRA_ECHO_PIN     = machine.ADC0
+	ULTRA_ECHO_PIN     = machine.D2
 	IR_FRONT_LEFT_PIN  = machine.ADC1
 	IR_FRONT_RIGHT_PIN = machine.ADC2
+	IR_REAR_LEFT_PIN   = machine.ADC3
+	IR_REAR_RIGHT_PIN  = machine.ADC0
 	DISPLAY_SDA_PIN    = machine.ADC4
 	DISPLAY_SCL_PIN    = machine.ADC5
 	STATUS_LED_PIN     = machine.D13
-	BUZZER_PIN         = machine.D8
-)
-
-const (
-	IR_FRONT_LEFT = iota
-	IR_FRONT_RIGHT
-	IR_SENSOR_COUNT
+	BUZZER_PIN         = machine.D11 // must be OC2A, toggled by Timer2
+	WAKE_BUTTON_PIN    = machine.D3
+	BATTERY_PIN        = AnalogChannel(6) // A6, Nano and Pro Mini only
 )
 
-// Motor is a DC motor controlled via H-bridge (L298N IN1/IN2).
-type Motor struct {
-	in1 machine.Pin
-	in2 machine.Pin
-}
-
-func NewMotor(in1, in2 machine.Pin) *Motor {
-	motor := &Motor{in1: in1, in2: in2}
-	motor.in1.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	motor.in2.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	return motor
-}
-
-func (m *Motor) Forward() {
-	m.in1.High()
-	m.in2.Low()
-}
-
-func (m *Motor) Backward() {
-	m.in1.Low()
-	m.in2.High()
-}
-
-func (m *Motor) Stop() {
-	m.in1.Low()
-	m.in2.Low()
-}
-
-// Robot holds pins and drivers for the desk pet hardware.
-type Robot struct {
-	leftMotor  *Motor
-	rightMotor *Motor
-	ultraTrig  machine.Pin
-	ultraEcho  machine.Pin
-	irSensors  [IR_SENSOR_COUNT]machine.ADC
-	statusLed  machine.Pin
-	buzzer     machine.Pin
-}
-
-// NewRobot initializes all hardware and returns a configured Robot.
-func NewRobot() *Robot {
-	robot := &Robot{
-		leftMotor:  NewMotor(LEFT_MOTOR_IN1, LEFT_MOTOR_IN2),
-		rightMotor: NewMotor(RIGHT_MOTOR_IN1, RIGHT_MOTOR_IN2),
-		ultraTrig:  ULTRA_TRIG_PIN,
-		ultraEcho:  ULTRA_ECHO_PIN,
-		statusLed:  STATUS_LED_PIN,
-		buzzer:     BUZZER_PIN,
+// REAR_IR_FITTED enables the optional rear IR edge sensors; set it to false if they are not wired.
+const REAR_IR_FITTED = true
+
+// BATTERY_PACK selects the discharge curve of the pack on the battery divider.
+const BATTERY_PACK = navlogic.PackLiPo1S
+
+// MOTOR_PWM drives both IN1 pins (D9 = OC1A, D10 = OC1B).
+var MOTOR_PWM = machine.Timer1
+
+// STORAGE holds the calibration record in the on-chip EEPROM.
+var STORAGE = EEPROM{}
+
+// NewRobot configures all board peripherals and returns them as a pet.Robot.
+func NewRobot() *pet.Robot {
+	MOTOR_PWM.Configure(machine.PWMConfig{Period: MOTOR_PWM_PERIOD})
+
+	robot := &pet.Robot{
+		LeftMotor:       NewMotor(MOTOR_PWM, LEFT_MOTOR_IN1, LEFT_MOTOR_IN2),
+		RightMotor:      NewMotor(MOTOR_PWM, RIGHT_MOTOR_IN1, RIGHT_MOTOR_IN2),
+		Ultrasonic:      NewUltrasonic(ULTRA_TRIG_PIN, ULTRA_ECHO_PIN),
+		StatusLed:       STATUS_LED_PIN,
+		Buzzer:          NewBuzzer(BUZZER_PIN),
+		Display:         NewDisplay(),
+		DisplayGeometry: DISPLAY,
+		Clock:           pet.NewSystemClock(),
+		Storage:         STORAGE,
+		Power:           IdleSleep{},
+		WakeButton:      NewButton(WAKE_BUTTON_PIN),
+		BatteryPack:     BATTERY_PACK,
+		Serial:          machine.Serial, // D0/D1 through the board's USB serial chip
 	}
 
-	robot.ultraTrig.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	robot.ultraEcho.Configure(machine.PinConfig{Mode: machine.PinInput})
-
 	machine.InitADC()
 
-	irPins := [IR_SENSOR_COUNT]machine.Pin{
+	irPins := [pet.IR_SENSOR_COUNT]machine.Pin{
 		IR_FRONT_LEFT_PIN,
 		IR_FRONT_RIGHT_PIN,
+		IR_REAR_LEFT_PIN,
+		IR_REAR_RIGHT_PIN,
 	}
 	for i, pin := range irPins {
-		robot.irSensors[i] = machine.ADC{Pin: pin}
-		robot.irSensors[i].Configure(machine.ADCConfig{})
+		if !REAR_IR_FITTED && (i == pet.IR_REAR_LEFT || i == pet.IR_REAR_RIGHT) {
+			continue
+		}
+		adc := machine.ADC{Pin: pin}
+		adc.Configure(machine.ADCConfig{})
+		robot.IRSensors[i] = adc
 	}
 
-	robot.statusLed.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	robot.buzzer.Configure(machine.PinConfig{Mode: machine.PinOutput})
-
-	return robot
-}
-
-func (r *Robot) BlinkLED(times int) {
-	for i := 0; i < times; i++ {
-		r.statusLed.High()
-		time.Sleep(time.Millisecond * 200)
-		r.statusLed.Low()
-		time.Sleep(time.Millisecond * 200)
+	if BATTERY_FITTED {
+		robot.Battery = Battery{adc: BATTERY_PIN}
 	}
-}
-
-func (r *Robot) Beep(duration time.Duration) {
-	r.buzzer.High()
-	time.Sleep(duration)
-	r.buzzer.Low()
-}
 
-func (r *Robot) BeepLoops(loops int) {
-	r.buzzer.High()
-	for i := 0; i < loops; i++ {
-	}
-	r.buzzer.Low()
-}
+	STATUS_LED_PIN.Configure(machine.PinConfig{Mode: machine.PinOutput})
 
-func (r *Robot) Initialize() {
-	r.BlinkLED(3)
-	r.Beep(time.Millisecond * 500)
+	return robot
 }
@@ -1,126 +1,99 @@
-//go:build bluepill
+//go:build tinygo && bluepill
 
 package main
 
 import (
 	"machine"
-	"time"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+	"github.com/GyeongHoKim/tiny-pet/internal/pet"
 )
 
 const (
 	LEFT_MOTOR_IN1     = machine.PA8
-	LEFT_MOTOR_IN2     = machine.PA9
-	RIGHT_MOTOR_IN1    = machine.PA10
-	RIGHT_MOTOR_IN2    = machine.PA11
+	LEFT_MOTOR_IN2     = machine.PB13
+	RIGHT_MOTOR_IN1    = machine.PA11
+	RIGHT_MOTOR_IN2    = machine.PB14
 	ULTRA_TRIG_PIN     = machine.PA12
 	ULTRA_ECHO_PIN     = machine.PB10
 	IR_FRONT_LEFT_PIN  = machine.PA1
 	IR_FRONT_RIGHT_PIN = machine.PA2
+	IR_REAR_LEFT_PIN   = machine.PA3
+	IR_REAR_RIGHT_PIN  = machine.PA4
 	DISPLAY_SDA_PIN    = machine.PB7
 	DISPLAY_SCL_PIN    = machine.PB6
 	STATUS_LED_PIN     = machine.PC13
-	BUZZER_PIN         = machine.PB15
-)
-
-const (
-	IR_FRONT_LEFT = iota
-	IR_FRONT_RIGHT
-	IR_SENSOR_COUNT
+	BUZZER_PIN         = machine.PB0
+	WAKE_BUTTON_PIN    = machine.PA0
+	BATTERY_PIN        = machine.PB1 // ADC9
 )
 
-// Motor is a DC motor controlled via H-bridge (L298N IN1/IN2).
-type Motor struct {
-	in1 machine.Pin
-	in2 machine.Pin
-}
-
-func NewMotor(in1, in2 machine.Pin) *Motor {
-	motor := &Motor{in1: in1, in2: in2}
-	motor.in1.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	motor.in2.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	return motor
-}
-
-func (m *Motor) Forward() {
-	m.in1.High()
-	m.in2.Low()
-}
-
-func (m *Motor) Backward() {
-	m.in1.Low()
-	m.in2.High()
-}
-
-func (m *Motor) Stop() {
-	m.in1.Low()
-	m.in2.Low()
-}
-
-// Robot holds pins and drivers for the desk pet hardware.
-type Robot struct {
-	leftMotor  *Motor
-	rightMotor *Motor
-	ultraTrig  machine.Pin
-	ultraEcho  machine.Pin
-	irSensors  [IR_SENSOR_COUNT]machine.ADC
-	statusLed  machine.Pin
-	buzzer     machine.Pin
-}
-
-// NewRobot initializes all hardware and returns a configured Robot.
-func NewRobot() *Robot {
-	robot := &Robot{
-		leftMotor:  NewMotor(LEFT_MOTOR_IN1, LEFT_MOTOR_IN2),
-		rightMotor: NewMotor(RIGHT_MOTOR_IN1, RIGHT_MOTOR_IN2),
-		ultraTrig:  ULTRA_TRIG_PIN,
-		ultraEcho:  ULTRA_ECHO_PIN,
-		statusLed:  STATUS_LED_PIN,
-		buzzer:     BUZZER_PIN,
+// REAR_IR_FITTED enables the optional rear IR edge sensors; set it to false if they are not wired.
+const REAR_IR_FITTED = true
+
+// BATTERY_FITTED enables the battery monitor on PB1; set it to false if the divider is not wired.
+const BATTERY_FITTED = true
+
+// BATTERY_PACK selects the discharge curve of the pack on the battery divider.
+const BATTERY_PACK = navlogic.PackLiPo1S
+
+// ADC_REF_MV is the ADC reference: the 3.3 V supply.
+const ADC_REF_MV = 3300
+
+// MOTOR_PWM drives both IN1 pins (PA8 = TIM1_CH1, PA11 = TIM1_CH4); PA9/PA10 stay free for the console on UART1.
+var MOTOR_PWM = machine.TIM1
+
+// BUZZER_PWM generates buzzer tones on PB0 (TIM3_CH3).
+var BUZZER_PWM = machine.TIM3
+
+// STORAGE holds the calibration record in the reserved last flash page.
+var STORAGE = FlashPage{addr: CALIBRATION_FLASH_PAGE}
+
+// NewRobot configures all board peripherals and returns them as a pet.Robot.
+func NewRobot() *pet.Robot {
+	MOTOR_PWM.Configure(machine.PWMConfig{Period: MOTOR_PWM_PERIOD})
+
+	robot := &pet.Robot{
+		LeftMotor:       NewMotor(MOTOR_PWM, LEFT_MOTOR_IN1, LEFT_MOTOR_IN2),
+		RightMotor:      NewMotor(MOTOR_PWM, RIGHT_MOTOR_IN1, RIGHT_MOTOR_IN2),
+		Ultrasonic:      NewUltrasonic(ULTRA_TRIG_PIN, ULTRA_ECHO_PIN),
+		StatusLed:       STATUS_LED_PIN,
+		Buzzer:          NewBuzzer(BUZZER_PWM, BUZZER_PIN),
+		Display:         NewDisplay(),
+		DisplayGeometry: DISPLAY,
+		Clock:           pet.NewSystemClock(),
+		Storage:         STORAGE,
+		// TinyGo's time.Sleep on Cortex-M already waits for its timer with WFI.
+		Power:       pet.SystemPower{},
+		WakeButton:  NewButton(WAKE_BUTTON_PIN),
+		BatteryPack: BATTERY_PACK,
+		Serial:      machine.Serial, // UART1: PA9 TX, PA10 RX to a USB serial adapter
 	}
 
-	robot.ultraTrig.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	robot.ultraEcho.Configure(machine.PinConfig{Mode: machine.PinInput})
-
 	machine.InitADC()
 
-	irPins := [IR_SENSOR_COUNT]machine.Pin{
+	irPins := [pet.IR_SENSOR_COUNT]machine.Pin{
 		IR_FRONT_LEFT_PIN,
 		IR_FRONT_RIGHT_PIN,
+		IR_REAR_LEFT_PIN,
+		IR_REAR_RIGHT_PIN,
 	}
 	for i, pin := range irPins {
-		robot.irSensors[i] = machine.ADC{Pin: pin}
-		robot.irSensors[i].Configure(machine.ADCConfig{})
+		if !REAR_IR_FITTED && (i == pet.IR_REAR_LEFT || i == pet.IR_REAR_RIGHT) {
+			continue
+		}
+		adc := machine.ADC{Pin: pin}
+		adc.Configure(machine.ADCConfig{})
+		robot.IRSensors[i] = adc
 	}
 
-	robot.statusLed.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	robot.buzzer.Configure(machine.PinConfig{Mode: machine.PinOutput})
-
-	return robot
-}
-
-func (r *Robot) BlinkLED(times int) {
-	for i := 0; i < times; i++ {
-		r.statusLed.High()
-		time.Sleep(time.Millisecond * 200)
-		r.statusLed.Low()
-		time.Sleep(time.Millisecond * 200)
+	if BATTERY_FITTED {
+		adc := machine.ADC{Pin: BATTERY_PIN}
+		adc.Configure(machine.ADCConfig{})
+		robot.Battery = Battery{adc: adc}
 	}
-}
-
-func (r *Robot) Beep(duration time.Duration) {
-	r.buzzer.High()
-	time.Sleep(duration)
-	r.buzzer.Low()
-}
 
-func (r *Robot) BeepLoops(loops int) {
-	r.buzzer.High()
-	for i := 0; i < loops; i++ {
-	}
-	r.buzzer.Low()
-}
+	STATUS_LED_PIN.Configure(machine.PinConfig{Mode: machine.PinOutput})
 
-func (r *Robot) Initialize() {
-	r.BlinkLED(3)
-	r.Beep(time.Millisecond * 500)
+	return robot
 }
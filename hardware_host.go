@@ -0,0 +1,49 @@
+//go:build !tinygo
+
+package main
+
+import (
+	"bufio"
+	"os"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/pet"
+)
+
+// NewRobot returns fake hardware so the firmware runs on a host without TinyGo or a board;
+// the console runs on stdin and stdout.
+func NewRobot() *pet.Robot {
+	robot := pet.NewFakeRobot().Robot
+	robot.Display = pet.NewFakeRenderer(DISPLAY)
+	robot.DisplayGeometry = DISPLAY
+	robot.Power = pet.SystemPower{}
+	robot.Serial = newStdioSerial()
+	return robot
+}
+
+// stdioSerial is a pet.SerialPort on stdin and stdout; a goroutine reads stdin so Buffered never blocks.
+type stdioSerial struct {
+	in chan byte
+}
+
+func newStdioSerial() *stdioSerial {
+	s := &stdioSerial{in: make(chan byte, 256)}
+	go s.read()
+	return s
+}
+
+func (s *stdioSerial) read() {
+	r := bufio.NewReader(os.Stdin)
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return
+		}
+		s.in <- b
+	}
+}
+
+func (s *stdioSerial) Buffered() int { return len(s.in) }
+
+func (s *stdioSerial) ReadByte() (byte, error) { return <-s.in, nil }
+
+func (s *stdioSerial) Write(p []byte) (int, error) { return os.Stdout.Write(p) }
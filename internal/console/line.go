@@ -0,0 +1,45 @@
+package console
+
+import "errors"
+
+// MaxLineLength is the longest command line the shell accepts, without the line ending.
+const MaxLineLength = 48
+
+var ErrLineTooLong = errors.New("line too long")
+
+// LineBuffer collects received bytes into command lines. CR or LF ends a line (so CRLF works), backspace
+// and DEL remove the last byte, and a line longer than MaxLineLength is dropped whole.
+type LineBuffer struct {
+	buf      [MaxLineLength]byte
+	n        int
+	overflow bool
+}
+
+// Feed adds one received byte. It returns a completed, non-empty line (valid until the next Feed),
+// or ErrLineTooLong at the end of a dropped line; otherwise the line is nil.
+func (l *LineBuffer) Feed(b byte) ([]byte, error) {
+	switch b {
+	case '\r', '\n':
+		n, overflow := l.n, l.overflow
+		l.n, l.overflow = 0, false
+		if overflow {
+			return nil, ErrLineTooLong
+		}
+		if n == 0 {
+			return nil, nil
+		}
+		return l.buf[:n], nil
+	case '\b', 0x7F:
+		if l.n > 0 {
+			l.n--
+		}
+	default:
+		if l.n == len(l.buf) {
+			l.overflow = true
+			return nil, nil
+		}
+		l.buf[l.n] = b
+		l.n++
+	}
+	return nil, nil
+}
@@ -0,0 +1,52 @@
+package console
+
+import (
+	"strings"
+	"testing"
+)
+
+// feed feeds input byte by byte and collects the completed lines, "!" standing for ErrLineTooLong.
+func feed(l *LineBuffer, input string) []string {
+	var lines []string
+	for i := 0; i < len(input); i++ {
+		line, err := l.Feed(input[i])
+		switch {
+		case err == ErrLineTooLong:
+			lines = append(lines, "!")
+		case err != nil:
+			lines = append(lines, "error: "+err.Error())
+		case line != nil:
+			lines = append(lines, string(line))
+		}
+	}
+	return lines
+}
+
+func TestLineBuffer(t *testing.T) {
+	long := strings.Repeat("x", MaxLineLength)
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"LF", "sensors\n", []string{"sensors"}},
+		{"CRLF", "sensors\r\nstop\r\n", []string{"sensors", "stop"}},
+		{"CR", "sensors\rstop\r", []string{"sensors", "stop"}},
+		{"no line ending yet", "sens", nil},
+		{"empty lines skipped", "\r\n\n\r", nil},
+		{"backspace", "sensx\bors\n", []string{"sensors"}},
+		{"DEL", "stopp\x7f\n", []string{"stop"}},
+		{"backspace on empty line", "\b\bstop\n", []string{"stop"}},
+		{"longest line", long + "\n", []string{long}},
+		{"too long", long + "y\nstop\n", []string{"!", "stop"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var l LineBuffer
+			got := feed(&l, tt.input)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("lines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
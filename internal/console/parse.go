@@ -0,0 +1,277 @@
+// Package console parses the serial command shell with no hardware dependencies (unit-testable with go test).
+// Parsing and printing work in fixed buffers, so the shell does not allocate on the boards.
+package console
+
+import "errors"
+
+// Commands, one per line:
+//
+//	help                          list the commands
+//	sensors                       ultrasonic distance, raw IR readings, edge flags and battery
+//	mode [walk|guard|interactive] show the mode and state, or switch mode (resumes autonomous driving)
+//	drive <linear> <angular> [ms] drive manually (-100..100 each), for ms if given
+//	stop                          stop the motors; manual control stays on
+//	calibrate                     run CalibrateComplete
+//	save                          save IR and obstacle thresholds, ultrasonic offset and motor trim
+//	face <name|number>            show an expression
+//	thresholds                    show the obstacle and IR edge thresholds
+//	threshold obstacle <cm>       set the obstacle threshold
+//	threshold edge [sensor] <raw> set one IR edge threshold, or all of them
+//	threshold offset <cm>         set the ultrasonic offset added to every reading
+//	telemetry on|off              start or stop the binary telemetry frames
+//	log                           dump the log lines kept in RAM
+const (
+	CmdHelp = iota
+	CmdSensors
+	CmdMode
+	CmdDrive
+	CmdStop
+	CmdCalibrate
+	CmdSave
+	CmdFace
+	CmdThresholds
+	CmdSetObstacle
+	CmdSetEdge
+	CmdSetOffset
+	CmdTelemetry
+	CmdLog
+)
+
+const (
+	MaxArgs       = 3
+	maxTokens     = MaxArgs + 2
+	MaxDriveSpeed = 100
+	MaxDriveMs    = 60000
+	MaxObstacleCm = 400
+	MaxOffsetCm   = 50
+)
+
+var (
+	ErrUnknownCommand  = errors.New("unknown command")
+	ErrMissingArgument = errors.New("missing argument")
+	ErrExtraArgument   = errors.New("too many arguments")
+	ErrBadArgument     = errors.New("bad argument")
+)
+
+// ModeNames are the behavior modes in the order of the pet's RANDOM_WALK_MODE, GUARD_MODE, INTERACTIVE_MODE.
+var ModeNames = [...]string{"walk", "guard", "interactive"}
+
+// FaceNames are the expressions in the order of the pet's EXPR_* constants.
+var FaceNames = [...]string{"neutral", "happy", "surprised", "scared", "excited", "blink", "sick", "sleepy", "sleeping", "lowbattery"}
+
+// StateNames are the navigation states, indexed by navlogic.StateIdle etc.
+var StateNames = [...]string{"idle", "moving", "obstacle", "edge", "interacting", "guarding", "alert"}
+
+// SwitchNames are the settings of an on/off argument, so off is 0 and on is 1.
+var SwitchNames = [...]string{"off", "on"}
+
+// Command is a parsed command line: its kind and up to MaxArgs numeric arguments.
+// Mode and face names are given as their index in ModeNames and FaceNames.
+type Command struct {
+	Kind  int
+	Args  [MaxArgs]int
+	NArgs int
+}
+
+// Parse parses one command line; words are separated by spaces or tabs.
+func Parse(line []byte) (Command, error) {
+	var tokens [maxTokens][]byte
+	n, err := split(line, &tokens)
+	if err != nil {
+		return Command{}, err
+	}
+	if n == 0 {
+		return Command{}, ErrUnknownCommand
+	}
+	name, args := tokens[0], tokens[1:n]
+
+	var cmd Command
+	switch {
+	case equal(name, "help"), equal(name, "?"):
+		cmd.Kind = CmdHelp
+		return cmd, wantArgs(args, 0, 0)
+	case equal(name, "sensors"):
+		cmd.Kind = CmdSensors
+		return cmd, wantArgs(args, 0, 0)
+	case equal(name, "stop"):
+		cmd.Kind = CmdStop
+		return cmd, wantArgs(args, 0, 0)
+	case equal(name, "calibrate"):
+		cmd.Kind = CmdCalibrate
+		return cmd, wantArgs(args, 0, 0)
+	case equal(name, "save"):
+		cmd.Kind = CmdSave
+		return cmd, wantArgs(args, 0, 0)
+	case equal(name, "log"):
+		cmd.Kind = CmdLog
+		return cmd, wantArgs(args, 0, 0)
+	case equal(name, "thresholds"):
+		cmd.Kind = CmdThresholds
+		return cmd, wantArgs(args, 0, 0)
+	case equal(name, "mode"):
+		cmd.Kind = CmdMode
+		if err := wantArgs(args, 0, 1); err != nil || len(args) == 0 {
+			return cmd, err
+		}
+		return cmd, cmd.addName(args[0], ModeNames[:])
+	case equal(name, "face"):
+		cmd.Kind = CmdFace
+		if err := wantArgs(args, 1, 1); err != nil {
+			return cmd, err
+		}
+		return cmd, cmd.addName(args[0], FaceNames[:])
+	case equal(name, "drive"):
+		cmd.Kind = CmdDrive
+		if err := wantArgs(args, 2, 3); err != nil {
+			return cmd, err
+		}
+		if err := cmd.addInt(args[0], -MaxDriveSpeed, MaxDriveSpeed); err != nil {
+			return cmd, err
+		}
+		if err := cmd.addInt(args[1], -MaxDriveSpeed, MaxDriveSpeed); err != nil {
+			return cmd, err
+		}
+		if len(args) == 3 {
+			return cmd, cmd.addInt(args[2], 1, MaxDriveMs)
+		}
+		return cmd, nil
+	case equal(name, "threshold"):
+		return parseThreshold(args)
+	case equal(name, "telemetry"):
+		cmd.Kind = CmdTelemetry
+		if err := wantArgs(args, 1, 1); err != nil {
+			return cmd, err
+		}
+		return cmd, cmd.addName(args[0], SwitchNames[:])
+	}
+	return cmd, ErrUnknownCommand
+}
+
+// parseThreshold parses the arguments of "threshold obstacle <cm>", "threshold edge [sensor] <raw>" and
+// "threshold offset <cm>".
+func parseThreshold(args [][]byte) (Command, error) {
+	var cmd Command
+	if len(args) == 0 {
+		return cmd, ErrMissingArgument
+	}
+	which, args := args[0], args[1:]
+	switch {
+	case equal(which, "obstacle"):
+		cmd.Kind = CmdSetObstacle
+		if err := wantArgs(args, 1, 1); err != nil {
+			return cmd, err
+		}
+		return cmd, cmd.addInt(args[0], 1, MaxObstacleCm)
+	case equal(which, "edge"):
+		cmd.Kind = CmdSetEdge
+		if err := wantArgs(args, 1, 2); err != nil {
+			return cmd, err
+		}
+		if len(args) == 2 {
+			if err := cmd.addInt(args[0], 0, 255); err != nil {
+				return cmd, err
+			}
+			args = args[1:]
+		}
+		return cmd, cmd.addInt(args[0], 0, 0xFFFF)
+	case equal(which, "offset"):
+		cmd.Kind = CmdSetOffset
+		if err := wantArgs(args, 1, 1); err != nil {
+			return cmd, err
+		}
+		return cmd, cmd.addInt(args[0], -MaxOffsetCm, MaxOffsetCm)
+	}
+	return cmd, ErrBadArgument
+}
+
+// split cuts line into words, failing with ErrExtraArgument when there are more than fit.
+func split(line []byte, tokens *[maxTokens][]byte) (int, error) {
+	n := 0
+	for i := 0; i < len(line); {
+		if isSpace(line[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(line) && !isSpace(line[i]) {
+			i++
+		}
+		if n == len(tokens) {
+			return n, ErrExtraArgument
+		}
+		tokens[n] = line[start:i]
+		n++
+	}
+	return n, nil
+}
+
+func wantArgs(args [][]byte, min, max int) error {
+	if len(args) < min {
+		return ErrMissingArgument
+	}
+	if len(args) > max {
+		return ErrExtraArgument
+	}
+	return nil
+}
+
+func (c *Command) addInt(token []byte, min, max int) error {
+	v, ok := parseInt(token)
+	if !ok || v < min || v > max {
+		return ErrBadArgument
+	}
+	c.Args[c.NArgs] = v
+	c.NArgs++
+	return nil
+}
+
+// addName adds the index of token in names; a number is taken as the index itself.
+func (c *Command) addName(token []byte, names []string) error {
+	for i, name := range names {
+		if equal(token, name) {
+			c.Args[c.NArgs] = i
+			c.NArgs++
+			return nil
+		}
+	}
+	return c.addInt(token, 0, len(names)-1)
+}
+
+// parseInt parses an optionally signed decimal integer of at most 6 digits.
+func parseInt(token []byte) (int, bool) {
+	negative := len(token) > 0 && token[0] == '-'
+	if negative {
+		token = token[1:]
+	}
+	if len(token) == 0 || len(token) > 6 {
+		return 0, false
+	}
+	v := 0
+	for _, b := range token {
+		if b < '0' || b > '9' {
+			return 0, false
+		}
+		v = v*10 + int(b-'0')
+	}
+	if negative {
+		v = -v
+	}
+	return v, true
+}
+
+// equal compares without converting token to a string, which would allocate on TinyGo.
+func equal(token []byte, s string) bool {
+	if len(token) != len(s) {
+		return false
+	}
+	for i := range token {
+		if token[i] != s[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t'
+}
@@ -0,0 +1,108 @@
+package console
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line string
+		want Command
+	}{
+		{"help", Command{Kind: CmdHelp}},
+		{"?", Command{Kind: CmdHelp}},
+		{"sensors", Command{Kind: CmdSensors}},
+		{"  sensors\t ", Command{Kind: CmdSensors}},
+		{"stop", Command{Kind: CmdStop}},
+		{"calibrate", Command{Kind: CmdCalibrate}},
+		{"save", Command{Kind: CmdSave}},
+		{"thresholds", Command{Kind: CmdThresholds}},
+		{"log", Command{Kind: CmdLog}},
+		{"mode", Command{Kind: CmdMode}},
+		{"mode walk", Command{Kind: CmdMode, Args: [MaxArgs]int{0}, NArgs: 1}},
+		{"mode guard", Command{Kind: CmdMode, Args: [MaxArgs]int{1}, NArgs: 1}},
+		{"mode interactive", Command{Kind: CmdMode, Args: [MaxArgs]int{2}, NArgs: 1}},
+		{"mode 2", Command{Kind: CmdMode, Args: [MaxArgs]int{2}, NArgs: 1}},
+		{"face happy", Command{Kind: CmdFace, Args: [MaxArgs]int{1}, NArgs: 1}},
+		{"face lowbattery", Command{Kind: CmdFace, Args: [MaxArgs]int{9}, NArgs: 1}},
+		{"face 3", Command{Kind: CmdFace, Args: [MaxArgs]int{3}, NArgs: 1}},
+		{"drive 60 0", Command{Kind: CmdDrive, Args: [MaxArgs]int{60, 0}, NArgs: 2}},
+		{"drive -40 -100 1500", Command{Kind: CmdDrive, Args: [MaxArgs]int{-40, -100, 1500}, NArgs: 3}},
+		{"threshold obstacle 25", Command{Kind: CmdSetObstacle, Args: [MaxArgs]int{25}, NArgs: 1}},
+		{"threshold edge 600", Command{Kind: CmdSetEdge, Args: [MaxArgs]int{600}, NArgs: 1}},
+		{"threshold edge 2 65535", Command{Kind: CmdSetEdge, Args: [MaxArgs]int{2, 65535}, NArgs: 2}},
+		{"threshold offset -3", Command{Kind: CmdSetOffset, Args: [MaxArgs]int{-3}, NArgs: 1}},
+		{"telemetry on", Command{Kind: CmdTelemetry, Args: [MaxArgs]int{1}, NArgs: 1}},
+		{"telemetry off", Command{Kind: CmdTelemetry, Args: [MaxArgs]int{0}, NArgs: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := Parse([]byte(tt.line))
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.line, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		line string
+		want error
+	}{
+		{"", ErrUnknownCommand},
+		{"   ", ErrUnknownCommand},
+		{"dance", ErrUnknownCommand},
+		{"Sensors", ErrUnknownCommand},
+		{"sensors now", ErrExtraArgument},
+		{"log all", ErrExtraArgument},
+		{"mode run", ErrBadArgument},
+		{"mode 3", ErrBadArgument},
+		{"mode walk guard", ErrExtraArgument},
+		{"face", ErrMissingArgument},
+		{"face grumpy", ErrBadArgument},
+		{"face 10", ErrBadArgument},
+		{"drive 50", ErrMissingArgument},
+		{"drive 101 0", ErrBadArgument},
+		{"drive 0 -101", ErrBadArgument},
+		{"drive 50 0 0", ErrBadArgument},
+		{"drive 50 0 60001", ErrBadArgument},
+		{"drive fast 0", ErrBadArgument},
+		{"drive 5x 0", ErrBadArgument},
+		{"drive - 0", ErrBadArgument},
+		{"drive 1 2 3 4", ErrExtraArgument},
+		{"drive 1 2 3 4 5 6", ErrExtraArgument},
+		{"threshold", ErrMissingArgument},
+		{"threshold sonar 5", ErrBadArgument},
+		{"threshold obstacle", ErrMissingArgument},
+		{"threshold obstacle 0", ErrBadArgument},
+		{"threshold obstacle 401", ErrBadArgument},
+		{"threshold edge 65536", ErrBadArgument},
+		{"threshold edge -1", ErrBadArgument},
+		{"threshold edge 1 2 3", ErrExtraArgument},
+		{"threshold edge 1234567", ErrBadArgument},
+		{"threshold offset", ErrMissingArgument},
+		{"threshold offset 51", ErrBadArgument},
+		{"telemetry", ErrMissingArgument},
+		{"telemetry yes", ErrBadArgument},
+		{"telemetry on off", ErrExtraArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.line)); err != tt.want {
+				t.Errorf("Parse(%q) error = %v, want %v", tt.line, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_DoesNotAllocate(t *testing.T) {
+	line := []byte("drive -40 -100 1500")
+	allocs := testing.AllocsPerRun(100, func() {
+		Parse(line)
+	})
+	if allocs != 0 {
+		t.Errorf("Parse allocates %.0f times per call", allocs)
+	}
+}
@@ -0,0 +1,70 @@
+package console
+
+import "io"
+
+// maxReplyLength is the longest reply line; longer ones are cut short.
+const maxReplyLength = 64
+
+// Printer builds a reply line in a fixed buffer and writes it with a CRLF ending, so replies need no fmt.
+type Printer struct {
+	w   io.Writer
+	buf [maxReplyLength + 2]byte
+	n   int
+}
+
+func NewPrinter(w io.Writer) *Printer {
+	return &Printer{w: w}
+}
+
+// Str appends s to the line.
+func (p *Printer) Str(s string) *Printer {
+	for i := 0; i < len(s); i++ {
+		p.byte(s[i])
+	}
+	return p
+}
+
+// Int appends v in decimal.
+func (p *Printer) Int(v int) *Printer {
+	if v < 0 {
+		p.byte('-')
+		v = -v
+	}
+	var digits [20]byte
+	i := len(digits)
+	for {
+		i--
+		digits[i] = byte('0' + v%10)
+		v /= 10
+		if v == 0 {
+			break
+		}
+	}
+	for ; i < len(digits); i++ {
+		p.byte(digits[i])
+	}
+	return p
+}
+
+// Bool appends 1 or 0.
+func (p *Printer) Bool(b bool) *Printer {
+	if b {
+		return p.Str("1")
+	}
+	return p.Str("0")
+}
+
+// End writes the line and starts the next one.
+func (p *Printer) End() {
+	n := p.n
+	p.buf[n], p.buf[n+1] = '\r', '\n'
+	p.n = 0
+	p.w.Write(p.buf[:n+2])
+}
+
+func (p *Printer) byte(b byte) {
+	if p.n < maxReplyLength {
+		p.buf[p.n] = b
+		p.n++
+	}
+}
@@ -0,0 +1,28 @@
+package console
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrinter(t *testing.T) {
+	var out bytes.Buffer
+	p := NewPrinter(&out)
+	p.Str("dist=").Int(23).Str(" ir=").Int(0).Str(",").Int(-512).Str(" edge=").Bool(true).Bool(false).End()
+	p.Str("ok").End()
+
+	if got, want := out.String(), "dist=23 ir=0,-512 edge=10\r\nok\r\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestPrinter_CutsLongLines(t *testing.T) {
+	var out bytes.Buffer
+	p := NewPrinter(&out)
+	p.Str(strings.Repeat("x", maxReplyLength)).Int(12345).End()
+
+	if got, want := out.String(), strings.Repeat("x", maxReplyLength)+"\r\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
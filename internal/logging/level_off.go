@@ -0,0 +1,7 @@
+//go:build !log_error && !log_warn && !log_info && !log_trace && !debug
+
+package logging
+
+// Level is the most verbose level logged, set by a log_<level> build tag; of several, the most
+// verbose wins.
+const Level = LevelOff
@@ -0,0 +1,5 @@
+//go:build log_trace || debug
+
+package logging
+
+const Level = LevelTrace
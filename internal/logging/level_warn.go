@@ -0,0 +1,5 @@
+//go:build log_warn && !log_info && !log_trace && !debug
+
+package logging
+
+const Level = LevelWarn
@@ -0,0 +1,112 @@
+// Package logging writes leveled, per-subsystem log lines without fmt or allocation.
+//
+// The level is chosen at compile time with one of the build tags log_error, log_warn, log_info or
+// log_trace (debug is the same as log_trace). The tags log_sensors, log_nav, log_display, log_calib and
+// log_power keep only those subsystems; with none of them every subsystem logs. Both Level and
+// Subsystems are constants, so Enabled folds to a constant at every call site and the compiler drops the
+// calls that are filtered out, strings and all; with no level tag release builds carry no logging. Lines
+// go to the serial port given to SetOutput, or with the log_ring tag into a RAM ring buffer that Dump
+// writes out, e.g. after a fault.
+package logging
+
+import (
+	"errors"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/console"
+)
+
+// Levels, most severe first.
+const (
+	LevelOff = iota
+	LevelError
+	LevelWarn
+	LevelInfo
+	LevelTrace
+)
+
+// Subsystems.
+const (
+	Sensors = 1 << iota
+	Nav
+	Display
+	Calib
+	Power
+)
+
+var ErrNoRing = errors.New("no log buffer")
+
+var levelTags = [...]string{"", "E", "W", "I", "T"}
+
+// Subsystems has the bit of each subsystem selected by a log_<subsystem> tag; zero logs every subsystem.
+const Subsystems = sensorsBit | navBit | displayBit | calibBit | powerBit
+
+// out formats lines for the serial port or the ring buffer; nil drops them.
+var out *console.Printer
+
+// Enabled reports whether messages of level from subsystem are logged.
+func Enabled(level, subsystem int) bool {
+	return level <= Level && (Subsystems == 0 || Subsystems&subsystem != 0)
+}
+
+// Error logs msg followed by values, e.g. Error(Calib, "IR sensor disconnected", 2) writes
+// "E calib: IR sensor disconnected 2".
+func Error(subsystem int, msg string, values ...int) {
+	if Enabled(LevelError, subsystem) {
+		write(LevelError, subsystem, msg, nil, values)
+	}
+}
+
+func Warn(subsystem int, msg string, values ...int) {
+	if Enabled(LevelWarn, subsystem) {
+		write(LevelWarn, subsystem, msg, nil, values)
+	}
+}
+
+func Info(subsystem int, msg string, values ...int) {
+	if Enabled(LevelInfo, subsystem) {
+		write(LevelInfo, subsystem, msg, nil, values)
+	}
+}
+
+func Trace(subsystem int, msg string, values ...int) {
+	if Enabled(LevelTrace, subsystem) {
+		write(LevelTrace, subsystem, msg, nil, values)
+	}
+}
+
+// Err logs msg and the text of err at level.
+func Err(level, subsystem int, msg string, err error) {
+	if Enabled(level, subsystem) {
+		write(level, subsystem, msg, err, nil)
+	}
+}
+
+func write(level, subsystem int, msg string, err error, values []int) {
+	if out == nil {
+		return
+	}
+	out.Str(levelTags[level]).Str(" ").Str(subsystemName(subsystem)).Str(": ").Str(msg)
+	if err != nil {
+		out.Str(": ").Str(err.Error())
+	}
+	for _, v := range values {
+		out.Str(" ").Int(v)
+	}
+	out.End()
+}
+
+func subsystemName(subsystem int) string {
+	switch subsystem {
+	case Sensors:
+		return "sensors"
+	case Nav:
+		return "nav"
+	case Display:
+		return "display"
+	case Calib:
+		return "calib"
+	case Power:
+		return "power"
+	}
+	return "?"
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/console"
+)
+
+func TestEnabled_OffWithoutTags(t *testing.T) {
+	if Level != LevelOff {
+		t.Skip("built with a log level tag")
+	}
+	for level := LevelError; level <= LevelTrace; level++ {
+		if Enabled(level, Nav) {
+			t.Errorf("Enabled(%d, Nav) = true without a log level tag", level)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	saved := out
+	out = console.NewPrinter(&buf)
+	defer func() { out = saved }()
+
+	write(LevelWarn, Calib, "Motor trim", nil, []int{-3, 5})
+	write(LevelError, Power, "Battery flat", nil, nil)
+	write(LevelInfo, Calib, "No saved calibration", errors.New("checksum mismatch"), nil)
+	write(LevelTrace, Nav, "state", nil, []int{2})
+
+	want := "W calib: Motor trim -3 5\r\n" +
+		"E power: Battery flat\r\n" +
+		"I calib: No saved calibration: checksum mismatch\r\n" +
+		"T nav: state 2\r\n"
+	if buf.String() != want {
+		t.Errorf("log =\n%q\nwant\n%q", buf.String(), want)
+	}
+}
+
+func TestLogging_Allocs(t *testing.T) {
+	saved := out
+	out = console.NewPrinter(io.Discard)
+	defer func() { out = saved }()
+	allocs := testing.AllocsPerRun(100, func() {
+		Info(Sensors, "IR sensor threshold", 2, 610)
+		write(LevelInfo, Sensors, "IR sensor threshold", nil, []int{2, 610})
+	})
+	if allocs != 0 {
+		t.Errorf("logging allocates %v times per line", allocs)
+	}
+}
@@ -0,0 +1,26 @@
+//go:build !log_ring
+
+package logging
+
+import (
+	"io"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/console"
+)
+
+// SetOutput sends log lines to w, normally the serial port; a nil w drops them.
+func SetOutput(w io.Writer) {
+	if Level == LevelOff || w == nil {
+		out = nil
+		return
+	}
+	out = console.NewPrinter(w)
+}
+
+// Dump writes the lines kept in RAM to w; without the log_ring tag there are none.
+func Dump(w io.Writer) error {
+	return ErrNoRing
+}
+
+// Reset forgets the lines kept in RAM; without the log_ring tag there are none.
+func Reset() {}
@@ -0,0 +1,37 @@
+//go:build log_ring
+
+package logging
+
+import (
+	"io"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/console"
+)
+
+// RingSize is the RAM kept for log lines; the oldest lines are dropped to make room.
+const RingSize = 192
+
+var (
+	ringBuf [RingSize]byte
+	ring    = NewRing(ringBuf[:])
+)
+
+func init() {
+	if Level != LevelOff {
+		out = console.NewPrinter(&ring)
+	}
+}
+
+// SetOutput does nothing: with the log_ring tag lines stay in RAM until Dump.
+func SetOutput(w io.Writer) {}
+
+// Dump writes the lines kept in RAM to w, oldest first.
+func Dump(w io.Writer) error {
+	_, err := ring.WriteTo(w)
+	return err
+}
+
+// Reset forgets the lines kept in RAM.
+func Reset() {
+	ring.Reset()
+}
@@ -0,0 +1,70 @@
+package logging
+
+import "io"
+
+// Ring keeps the most recent log lines in a fixed buffer. Writing past its size drops the oldest lines
+// whole, so a dump never starts in the middle of a line.
+type Ring struct {
+	buf   []byte
+	start int
+	n     int
+}
+
+func NewRing(buf []byte) Ring {
+	return Ring{buf: buf}
+}
+
+// Write appends p, normally one line ending in '\n'. Only the tail of a p longer than the buffer is kept.
+func (r *Ring) Write(p []byte) (int, error) {
+	written := len(p)
+	if len(r.buf) == 0 {
+		return written, nil
+	}
+	if len(p) > len(r.buf) {
+		p = p[len(p)-len(r.buf):]
+	}
+	if free := len(r.buf) - r.n; len(p) > free {
+		r.drop(len(p) - free)
+	}
+	for _, b := range p {
+		r.buf[(r.start+r.n)%len(r.buf)] = b
+		r.n++
+	}
+	return written, nil
+}
+
+// drop removes at least n bytes from the front, up to the end of a line.
+func (r *Ring) drop(n int) {
+	for dropped := 1; r.n > 0; dropped++ {
+		b := r.buf[r.start]
+		r.start = (r.start + 1) % len(r.buf)
+		r.n--
+		if dropped >= n && b == '\n' {
+			return
+		}
+	}
+}
+
+// Len returns how many bytes are kept.
+func (r *Ring) Len() int {
+	return r.n
+}
+
+// WriteTo writes the kept bytes to w, oldest first.
+func (r *Ring) WriteTo(w io.Writer) (int64, error) {
+	first := r.n
+	if r.start+first > len(r.buf) {
+		first = len(r.buf) - r.start
+	}
+	n, err := w.Write(r.buf[r.start : r.start+first])
+	if err != nil || first == r.n {
+		return int64(n), err
+	}
+	m, err := w.Write(r.buf[:r.n-first])
+	return int64(n + m), err
+}
+
+// Reset empties the ring.
+func (r *Ring) Reset() {
+	r.start, r.n = 0, 0
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRing(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  int
+		lines []string
+		want  string
+	}{
+		{"empty", 16, nil, ""},
+		{"fits", 16, []string{"a\n", "bc\n"}, "a\nbc\n"},
+		{"exactly full", 6, []string{"a\n", "bc\n", "d"}, "a\nbc\nd"},
+		{"drops the oldest line", 8, []string{"one\n", "two\n", "six\n"}, "two\nsix\n"},
+		{"drops several lines", 8, []string{"a\n", "b\n", "c\n", "long\n"}, "c\nlong\n"},
+		{"wraps around", 10, []string{"abc\n", "def\n", "gh\n", "ij\n"}, "def\ngh\nij\n"},
+		{"keeps the tail of a long line", 4, []string{"a\n", "abcdefg\n"}, "efg\n"},
+		{"no buffer", 0, []string{"a\n"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRing(make([]byte, tt.size))
+			for _, line := range tt.lines {
+				if n, err := r.Write([]byte(line)); n != len(line) || err != nil {
+					t.Fatalf("Write(%q) = %d, %v", line, n, err)
+				}
+			}
+			var buf bytes.Buffer
+			n, err := r.WriteTo(&buf)
+			if err != nil || buf.String() != tt.want || int(n) != len(tt.want) || r.Len() != len(tt.want) {
+				t.Errorf("WriteTo = %q (%d, %v), want %q", buf.String(), n, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRing_Reset(t *testing.T) {
+	r := NewRing(make([]byte, 8))
+	r.Write([]byte("abc\n"))
+	r.Reset()
+	r.Write([]byte("d\n"))
+	var buf bytes.Buffer
+	r.WriteTo(&buf)
+	if buf.String() != "d\n" {
+		t.Errorf("after Reset = %q, want %q", buf.String(), "d\n")
+	}
+}
+
+func TestRing_ManyLines(t *testing.T) {
+	// Whatever was written, the ring holds whole lines only, the newest last.
+	r := NewRing(make([]byte, 50))
+	var all []string
+	for i := 0; i < 40; i++ {
+		line := strings.Repeat("x", i%7) + "\n"
+		all = append(all, line)
+		r.Write([]byte(line))
+	}
+	var buf bytes.Buffer
+	r.WriteTo(&buf)
+	got := buf.String()
+	if !strings.HasSuffix(strings.Join(all, ""), got) || !strings.HasSuffix(got, all[len(all)-1]) {
+		t.Errorf("ring = %q, want a tail of whole lines", got)
+	}
+	if len(got) < 50-7 {
+		t.Errorf("ring keeps %d bytes, want close to its 50", len(got))
+	}
+}
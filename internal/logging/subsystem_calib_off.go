@@ -0,0 +1,5 @@
+//go:build !log_calib
+
+package logging
+
+const calibBit = 0
@@ -0,0 +1,5 @@
+//go:build !log_display
+
+package logging
+
+const displayBit = 0
@@ -0,0 +1,5 @@
+//go:build !log_nav
+
+package logging
+
+const navBit = 0
@@ -0,0 +1,5 @@
+//go:build !log_power
+
+package logging
+
+const powerBit = 0
@@ -0,0 +1,5 @@
+//go:build log_sensors
+
+package logging
+
+const sensorsBit = Sensors
@@ -0,0 +1,119 @@
+package navlogic
+
+// Battery packs the monitor knows the discharge curve of.
+const (
+	PackFourAA = iota
+	PackLiPo1S
+	PackLiPo2S
+)
+
+// Battery levels reported by BatteryMonitor.
+const (
+	BatteryOK = iota
+	BatteryLow
+	BatteryCritical
+	BatteryAbsent
+)
+
+const (
+	BatteryLowPercent        = 20
+	BatteryCriticalPercent   = 5
+	BatteryHysteresisPercent = 5
+	BatteryAbsentMv          = 2000
+	BatterySmoothingShift    = 3
+	BatteryLowSpeedPct       = 50
+)
+
+// cellPoint is one point of a per-cell discharge curve, in falling voltage order.
+type cellPoint struct {
+	mv      uint16
+	percent uint8
+}
+
+// Resting discharge curves per cell: alkaline AA and LiPo / Li-ion.
+var (
+	alkalineCurve = [...]cellPoint{{1600, 100}, {1500, 90}, {1400, 70}, {1300, 45}, {1200, 25}, {1100, 10}, {1000, 0}}
+	lipoCurve     = [...]cellPoint{{4200, 100}, {4100, 90}, {4000, 78}, {3900, 65}, {3800, 50}, {3750, 40}, {3700, 30}, {3650, 20}, {3600, 10}, {3500, 5}, {3300, 0}}
+)
+
+// BatteryPercent returns the charge (0..100) of pack at packMv, interpolating its per-cell discharge curve.
+func BatteryPercent(pack int, packMv uint16) int {
+	curve, cells := lipoCurve[:], uint16(1)
+	switch pack {
+	case PackFourAA:
+		curve, cells = alkalineCurve[:], 4
+	case PackLiPo2S:
+		cells = 2
+	}
+	mv := packMv / cells
+	if mv >= curve[0].mv {
+		return int(curve[0].percent)
+	}
+	for i := 1; i < len(curve); i++ {
+		hi, lo := curve[i-1], curve[i]
+		if mv >= lo.mv {
+			return int(lo.percent) + int(hi.percent-lo.percent)*int(mv-lo.mv)/int(hi.mv-lo.mv)
+		}
+	}
+	return 0
+}
+
+// AdcToMillivolts converts a 16-bit ADC reading (machine.ADC scale) taken through a divider of
+// ratio divider (pack voltage / pin voltage) against a reference of refMv.
+func AdcToMillivolts(raw uint16, refMv, divider uint32) uint16 {
+	return uint16(uint32(raw) * refMv * divider >> 16)
+}
+
+// BatteryMonitor smooths pack voltage readings, so motor current sag does not trip it, and tracks the level
+// with BatteryHysteresisPercent of hysteresis. Readings under BatteryAbsentMv mean no battery is connected
+// (the pet runs from USB) and report BatteryAbsent.
+type BatteryMonitor struct {
+	Pack     int
+	smoothMv uint32 // pack mV << BatterySmoothingShift
+	level    int
+	seeded   bool
+}
+
+// Update folds in a pack voltage reading and returns the battery level.
+func (b *BatteryMonitor) Update(mv uint16) int {
+	if mv < BatteryAbsentMv {
+		b.seeded = false
+		b.level = BatteryAbsent
+		return b.level
+	}
+	if !b.seeded {
+		b.seeded = true
+		b.smoothMv = uint32(mv) << BatterySmoothingShift
+		b.level = BatteryOK
+	} else {
+		b.smoothMv = b.smoothMv - b.smoothMv>>BatterySmoothingShift + uint32(mv)
+	}
+
+	percent := b.Percent()
+	switch {
+	case percent <= BatteryCriticalPercent:
+		b.level = BatteryCritical
+	case percent <= BatteryLowPercent:
+		if b.level != BatteryCritical || percent > BatteryCriticalPercent+BatteryHysteresisPercent {
+			b.level = BatteryLow
+		}
+	case b.level == BatteryOK || percent > BatteryLowPercent+BatteryHysteresisPercent:
+		b.level = BatteryOK
+	case b.level == BatteryCritical:
+		b.level = BatteryLow
+	}
+	return b.level
+}
+
+// Millivolts is the smoothed pack voltage.
+func (b *BatteryMonitor) Millivolts() uint16 {
+	return uint16(b.smoothMv >> BatterySmoothingShift)
+}
+
+func (b *BatteryMonitor) Percent() int {
+	return BatteryPercent(b.Pack, b.Millivolts())
+}
+
+func (b *BatteryMonitor) Level() int {
+	return b.level
+}
@@ -0,0 +1,90 @@
+package navlogic
+
+import "testing"
+
+func TestBatteryPercent(t *testing.T) {
+	tests := []struct {
+		name string
+		pack int
+		mv   uint16
+		want int
+	}{
+		{"1S full", PackLiPo1S, 4200, 100},
+		{"1S overcharged", PackLiPo1S, 4300, 100},
+		{"1S nominal", PackLiPo1S, 3800, 50},
+		{"1S between points", PackLiPo1S, 3850, 57},
+		{"1S low", PackLiPo1S, 3650, 20},
+		{"1S empty", PackLiPo1S, 3300, 0},
+		{"1S flat", PackLiPo1S, 3000, 0},
+		{"2S nominal", PackLiPo2S, 7600, 50},
+		{"2S empty", PackLiPo2S, 6600, 0},
+		{"4xAA fresh", PackFourAA, 6400, 100},
+		{"4xAA half", PackFourAA, 5200, 45},
+		{"4xAA empty", PackFourAA, 4000, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BatteryPercent(tt.pack, tt.mv); got != tt.want {
+				t.Errorf("BatteryPercent(%d, %d) = %d, want %d", tt.pack, tt.mv, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdcToMillivolts(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     uint16
+		refMv   uint32
+		divider uint32
+		want    uint16
+	}{
+		{"zero", 0, 5000, 3, 0},
+		{"half scale, 5V reference", 32768, 5000, 3, 7500},
+		{"1S full on 3.3V reference", 27803, 3300, 3, 4200},
+		{"no divider", 32768, 3300, 1, 1650},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AdcToMillivolts(tt.raw, tt.refMv, tt.divider)
+			if diff := int(got) - int(tt.want); diff < -2 || diff > 2 {
+				t.Errorf("AdcToMillivolts(%d, %d, %d) = %d, want %d", tt.raw, tt.refMv, tt.divider, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatteryMonitor_Levels(t *testing.T) {
+	tests := []struct {
+		name     string
+		readings []uint16
+		want     int
+	}{
+		{"full", []uint16{4100}, BatteryOK},
+		{"first reading is taken as is", []uint16{3600}, BatteryLow},
+		{"critical", []uint16{3450}, BatteryCritical},
+		{"no battery", []uint16{4100, 0}, BatteryAbsent},
+		{"battery connected", []uint16{0, 3900}, BatteryOK},
+		{"motor sag is smoothed", []uint16{3700, 3400, 3700}, BatteryOK},
+		{"sustained drop", []uint16{3700, 3550, 3550, 3550, 3550, 3550, 3550, 3550, 3550, 3550, 3550}, BatteryLow},
+		{"low stays low just above threshold", []uint16{3640, 3680}, BatteryLow},
+		{"low recovers past hysteresis", []uint16{3640, 3800, 3800, 3800, 3800, 3800, 3800, 3800, 3800}, BatteryOK},
+		{"critical stays critical just above threshold", []uint16{3450, 3520}, BatteryCritical},
+		{"critical recovers to low", []uint16{3450, 3650, 3650, 3650, 3650, 3650, 3650, 3650, 3650, 3650, 3650, 3650, 3650, 3650, 3650}, BatteryLow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := BatteryMonitor{Pack: PackLiPo1S}
+			var got int
+			for _, mv := range tt.readings {
+				got = b.Update(mv)
+			}
+			if got != tt.want {
+				t.Errorf("level = %d, want %d (%d mV, %d%%)", got, tt.want, b.Millivolts(), b.Percent())
+			}
+			if b.Level() != got {
+				t.Errorf("Level() = %d, Update returned %d", b.Level(), got)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package navlogic
+
+// BlinkPattern flashes Count times (OnMs on, OffMs off), then stays off for PauseMs before it repeats.
+// A single flash with no off time or pause keeps the output on.
+type BlinkPattern struct {
+	OnMs    uint16
+	OffMs   uint16
+	Count   uint8
+	PauseMs uint16
+}
+
+func (p BlinkPattern) cycleMs() uint32 {
+	return uint32(p.Count)*uint32(p.OnMs+p.OffMs) + uint32(p.PauseMs)
+}
+
+// BlinkPlayer runs a BlinkPattern over time, once or repeating; the main loop advances it by the elapsed time.
+type BlinkPlayer struct {
+	pattern   BlinkPattern
+	repeat    bool
+	active    bool
+	started   bool
+	elapsedMs uint32
+}
+
+// Start replaces the running pattern. Like MotionPlan, time counts from the first Advance.
+func (b *BlinkPlayer) Start(pattern BlinkPattern, repeat bool) {
+	b.pattern, b.repeat = pattern, repeat
+	b.active = pattern.cycleMs() > 0
+	b.started = false
+	b.elapsedMs = 0
+}
+
+func (b *BlinkPlayer) Stop() {
+	b.active = false
+}
+
+func (b *BlinkPlayer) Active() bool {
+	return b.active
+}
+
+// Advance moves the pattern on by elapsedMs and reports whether the output is on; a finished one-shot pattern is off.
+func (b *BlinkPlayer) Advance(elapsedMs uint32) bool {
+	if !b.active {
+		return false
+	}
+	if !b.started {
+		b.started = true
+	} else {
+		b.elapsedMs += elapsedMs
+	}
+	cycle := b.pattern.cycleMs()
+	if b.elapsedMs >= cycle {
+		if !b.repeat {
+			b.active = false
+			return false
+		}
+		b.elapsedMs %= cycle
+	}
+	flash := uint32(b.pattern.OnMs + b.pattern.OffMs)
+	if b.elapsedMs >= uint32(b.pattern.Count)*flash {
+		return false
+	}
+	return b.elapsedMs%flash < uint32(b.pattern.OnMs)
+}
@@ -0,0 +1,50 @@
+package navlogic
+
+import "testing"
+
+func TestBlinkPlayer(t *testing.T) {
+	heartbeat := BlinkPattern{OnMs: 80, OffMs: 120, Count: 2, PauseMs: 600}
+	tests := []struct {
+		name    string
+		pattern BlinkPattern
+		repeat  bool
+		stepMs  uint32
+		want    string
+	}{
+		{"heartbeat", heartbeat, true, 40, "##___##__________________##___##"},
+		{"one-shot blink code stops", BlinkPattern{OnMs: 100, OffMs: 100, Count: 3}, false, 100, "#_#_#_______"},
+		{"solid", BlinkPattern{OnMs: 1000, Count: 1}, true, 300, "##########"},
+		{"strobe", BlinkPattern{OnMs: 50, OffMs: 50, Count: 1}, true, 50, "#_#_#_#_"},
+		{"empty pattern stays off", BlinkPattern{}, true, 100, "____"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b BlinkPlayer
+			b.Start(tt.pattern, tt.repeat)
+			got := make([]byte, len(tt.want))
+			for i := range got {
+				got[i] = '_'
+				if b.Advance(tt.stepMs) {
+					got[i] = '#'
+				}
+			}
+			if string(got) != tt.want {
+				t.Errorf("every %d ms: %s, want %s", tt.stepMs, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlinkPlayer_StopAndRestart(t *testing.T) {
+	var b BlinkPlayer
+	b.Start(BlinkPattern{OnMs: 100, Count: 1}, true)
+	b.Advance(10)
+	b.Stop()
+	if b.Active() || b.Advance(10) {
+		t.Error("stopped pattern still on")
+	}
+	b.Start(BlinkPattern{OnMs: 10, OffMs: 100, Count: 1}, false)
+	if !b.Advance(1000) {
+		t.Error("restarted pattern not on at its first advance")
+	}
+}
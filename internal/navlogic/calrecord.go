@@ -0,0 +1,96 @@
+package navlogic
+
+import "errors"
+
+// Calibration record layout (little endian), CalibrationRecordSize bytes:
+//
+//	0  'T' 'P'          magic
+//	2  version          CalibrationRecordVersion
+//	3  payload length   calibrationPayloadSize
+//	4  IR thresholds    CalibrationIRSensors × uint16
+//	12 ultrasonic offset int8, cm, set by hand
+//	13 left trim        int8, percent
+//	14 right trim       int8, percent
+//	15 obstacle threshold uint16, cm
+//	17 CRC16 of bytes 0..16
+const (
+	CalibrationRecordVersion = 2
+	CalibrationIRSensors     = 4
+	calibrationHeaderSize    = 4
+	calibrationPayloadSize   = CalibrationIRSensors*2 + 5
+	CalibrationRecordSize    = calibrationHeaderSize + calibrationPayloadSize + 2
+)
+
+var (
+	ErrRecordMissing  = errors.New("calibration record missing")
+	ErrRecordVersion  = errors.New("calibration record version mismatch")
+	ErrRecordChecksum = errors.New("calibration record checksum mismatch")
+)
+
+// CalibrationRecord is what the pet persists between boots so it can skip calibration.
+type CalibrationRecord struct {
+	IRThresholds     [CalibrationIRSensors]uint16
+	UltrasonicOffset int8 // not measured by calibration; set by hand (console "threshold offset")
+	LeftTrim         int8
+	RightTrim        int8
+	ObstacleCm       uint16
+}
+
+// Encode writes the record with its header and checksum into buf.
+func (r CalibrationRecord) Encode(buf *[CalibrationRecordSize]byte) {
+	buf[0], buf[1] = 'T', 'P'
+	buf[2] = CalibrationRecordVersion
+	buf[3] = calibrationPayloadSize
+	i := calibrationHeaderSize
+	for _, t := range r.IRThresholds {
+		buf[i], buf[i+1] = byte(t), byte(t>>8)
+		i += 2
+	}
+	buf[i] = byte(r.UltrasonicOffset)
+	buf[i+1] = byte(r.LeftTrim)
+	buf[i+2] = byte(r.RightTrim)
+	buf[i+3], buf[i+4] = byte(r.ObstacleCm), byte(r.ObstacleCm>>8)
+	crc := CRC16(buf[:CalibrationRecordSize-2])
+	buf[CalibrationRecordSize-2], buf[CalibrationRecordSize-1] = byte(crc), byte(crc>>8)
+}
+
+// DecodeCalibrationRecord validates and decodes buf; erased storage reads as ErrRecordMissing.
+func DecodeCalibrationRecord(buf *[CalibrationRecordSize]byte) (CalibrationRecord, error) {
+	var r CalibrationRecord
+	if buf[0] != 'T' || buf[1] != 'P' {
+		return r, ErrRecordMissing
+	}
+	if buf[2] != CalibrationRecordVersion || buf[3] != calibrationPayloadSize {
+		return r, ErrRecordVersion
+	}
+	crc := uint16(buf[CalibrationRecordSize-2]) | uint16(buf[CalibrationRecordSize-1])<<8
+	if crc != CRC16(buf[:CalibrationRecordSize-2]) {
+		return r, ErrRecordChecksum
+	}
+	i := calibrationHeaderSize
+	for n := range r.IRThresholds {
+		r.IRThresholds[n] = uint16(buf[i]) | uint16(buf[i+1])<<8
+		i += 2
+	}
+	r.UltrasonicOffset = int8(buf[i])
+	r.LeftTrim = int8(buf[i+1])
+	r.RightTrim = int8(buf[i+2])
+	r.ObstacleCm = uint16(buf[i+3]) | uint16(buf[i+4])<<8
+	return r, nil
+}
+
+// CRC16 is CRC-16/CCITT-FALSE (poly 0x1021, init 0xFFFF), computed bitwise to keep flash use small.
+func CRC16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,61 @@
+package navlogic
+
+import "testing"
+
+func TestCRC16(t *testing.T) {
+	if got := CRC16([]byte("123456789")); got != 0x29B1 {
+		t.Errorf("CRC16(check string) = %#04x, want 0x29b1", got)
+	}
+	if got := CRC16(nil); got != 0xFFFF {
+		t.Errorf("CRC16(nil) = %#04x, want 0xffff", got)
+	}
+}
+
+func TestCalibrationRecord_RoundTrip(t *testing.T) {
+	want := CalibrationRecord{
+		IRThresholds:     [CalibrationIRSensors]uint16{20000, 19500, 0, 0xFFFF},
+		UltrasonicOffset: -3,
+		LeftTrim:         5,
+		RightTrim:        -7,
+		ObstacleCm:       300,
+	}
+	var buf [CalibrationRecordSize]byte
+	want.Encode(&buf)
+	got, err := DecodeCalibrationRecord(&buf)
+	if err != nil || got != want {
+		t.Errorf("round trip = (%+v, %v), want (%+v, nil)", got, err, want)
+	}
+}
+
+func TestDecodeCalibrationRecord_Invalid(t *testing.T) {
+	valid := func() [CalibrationRecordSize]byte {
+		var buf [CalibrationRecordSize]byte
+		CalibrationRecord{IRThresholds: [CalibrationIRSensors]uint16{1, 2, 3, 4}}.Encode(&buf)
+		return buf
+	}
+	tests := []struct {
+		name    string
+		corrupt func(buf *[CalibrationRecordSize]byte)
+		want    error
+	}{
+		{"erased EEPROM", func(buf *[CalibrationRecordSize]byte) {
+			for i := range buf {
+				buf[i] = 0xFF
+			}
+		}, ErrRecordMissing},
+		{"zeroed storage", func(buf *[CalibrationRecordSize]byte) { *buf = [CalibrationRecordSize]byte{} }, ErrRecordMissing},
+		{"older version", func(buf *[CalibrationRecordSize]byte) { buf[2] = CalibrationRecordVersion - 1 }, ErrRecordVersion},
+		{"wrong payload length", func(buf *[CalibrationRecordSize]byte) { buf[3]++ }, ErrRecordVersion},
+		{"flipped threshold bit", func(buf *[CalibrationRecordSize]byte) { buf[calibrationHeaderSize] ^= 0x10 }, ErrRecordChecksum},
+		{"flipped checksum bit", func(buf *[CalibrationRecordSize]byte) { buf[CalibrationRecordSize-1] ^= 0x01 }, ErrRecordChecksum},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := valid()
+			tt.corrupt(&buf)
+			if _, err := DecodeCalibrationRecord(&buf); err != tt.want {
+				t.Errorf("DecodeCalibrationRecord error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -5,12 +5,39 @@ const (
 	TimeoutDistance   = -1
 )
 
-// EchoCountToDistanceCm converts echo loop count to distance in cm; returns TimeoutDistance (-1) if invalid.
-func EchoCountToDistanceCm(echoCount int) int {
-	if echoCount <= 0 {
+// Ultrasonic reading statuses.
+const (
+	RangeOK         = iota
+	RangeOutOfRange // the echo came back too late: nothing within range
+	RangeStuckHigh  // the echo pin rose and never fell: sensor fault
+	RangeNoEcho     // the echo pin never rose after the trigger: sensor unplugged or unpowered
+)
+
+// RangeReading is one ultrasonic measurement; Cm is only meaningful when Status is RangeOK.
+type RangeReading struct {
+	Cm     int
+	Status int
+}
+
+// RangeFromCm wraps a plain distance, TimeoutDistance meaning out of range.
+func RangeFromCm(cm int) RangeReading {
+	if cm == TimeoutDistance {
+		return RangeReading{Cm: TimeoutDistance, Status: RangeOutOfRange}
+	}
+	return RangeReading{Cm: cm}
+}
+
+// Distance returns Cm, or TimeoutDistance for any status but RangeOK.
+func (r RangeReading) Distance() int {
+	if r.Status != RangeOK {
 		return TimeoutDistance
 	}
-	return echoCount / MicrosecondsPerCm
+	return r.Cm
+}
+
+// IsFault reports whether the reading points to a broken or disconnected sensor rather than open space.
+func (r RangeReading) IsFault() bool {
+	return r.Status == RangeStuckHigh || r.Status == RangeNoEcho
 }
 
 // EchoMicrosecondsToDistanceCm converts echo pulse width (µs) to distance in cm; returns TimeoutDistance (-1) if invalid.
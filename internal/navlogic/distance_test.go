@@ -2,35 +2,6 @@ package navlogic
 
 import "testing"
 
-func TestEchoCountToDistanceCm(t *testing.T) {
-	tests := []struct {
-		name      string
-		echoCount int
-		want      int
-	}{
-		{"58 counts = 1 cm", 58, 1},
-		{"116 counts = 2 cm", 116, 2},
-		{"580 counts = 10 cm", 580, 10},
-		{"1160 counts = 20 cm (obstacle threshold)", 1160, 20},
-		{"5800 counts = 100 cm", 5800, 100},
-		{"0 counts = timeout", 0, TimeoutDistance},
-		{"negative counts = timeout", -1, TimeoutDistance},
-		{"57 counts = 0 cm (below 1 cm)", 57, 0},
-		{"59 counts = 1 cm", 59, 1},
-		{"1 count = 0 cm", 1, 0},
-		{"29 counts = 0 cm (half of threshold)", 29, 0},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := EchoCountToDistanceCm(tt.echoCount)
-			if got != tt.want {
-				t.Errorf("EchoCountToDistanceCm(%d) = %d, want %d", tt.echoCount, got, tt.want)
-			}
-		})
-	}
-}
-
 func TestEchoMicrosecondsToDistanceCm(t *testing.T) {
 	tests := []struct {
 		name string
@@ -84,8 +55,8 @@ func TestIsWithinThreshold(t *testing.T) {
 	}
 }
 
-func BenchmarkEchoCountToDistanceCm(b *testing.B) {
+func BenchmarkEchoMicrosecondsToDistanceCm(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		EchoCountToDistanceCm(1160)
+		EchoMicrosecondsToDistanceCm(1160)
 	}
 }
@@ -0,0 +1,46 @@
+package navlogic
+
+const (
+	WanderStep   = 12
+	WanderPeriod = 20
+)
+
+// MixDrive mixes a linear speed and an angular rate (positive turns left) into signed wheel speeds.
+// If either wheel would exceed MaxSpeed both are scaled down, keeping the arc's curvature.
+func MixDrive(linear, angular int) (left, right int) {
+	left = linear - angular
+	right = linear + angular
+	peak := abs(left)
+	if abs(right) > peak {
+		peak = abs(right)
+	}
+	if peak > MaxSpeed {
+		left = left * MaxSpeed / peak
+		right = right * MaxSpeed / peak
+	}
+	return left, right
+}
+
+// WanderAngular picks the gentle turn rate for the next stretch of random walk from seed:
+// one of -2, -1, 0, 1, 2 times WanderStep, straight being the most likely.
+func WanderAngular(seed uint8) int {
+	switch (uint16(seed)*73 + 41) % 7 {
+	case 0:
+		return -2 * WanderStep
+	case 1:
+		return -WanderStep
+	case 5:
+		return WanderStep
+	case 6:
+		return 2 * WanderStep
+	default:
+		return 0
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
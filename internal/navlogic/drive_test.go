@@ -0,0 +1,46 @@
+package navlogic
+
+import "testing"
+
+func TestMixDrive(t *testing.T) {
+	tests := []struct {
+		name            string
+		linear, angular int
+		wantL, wantR    int
+	}{
+		{"straight", 60, 0, 60, 60},
+		{"gentle left arc", 60, 20, 40, 80},
+		{"gentle right arc", 60, -20, 80, 40},
+		{"spin left", 0, 50, -50, 50},
+		{"reverse arc", -60, 20, -80, -40},
+		{"saturated arc keeps ratio", 100, 50, 33, 100},
+		{"saturated reverse arc", -100, -50, -33, -100},
+		{"saturated spin", 0, 200, -100, 100},
+		{"stopped", 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, r := MixDrive(tt.linear, tt.angular)
+			if l != tt.wantL || r != tt.wantR {
+				t.Errorf("MixDrive(%d, %d) = (%d, %d), want (%d, %d)", tt.linear, tt.angular, l, r, tt.wantL, tt.wantR)
+			}
+		})
+	}
+}
+
+func TestWanderAngular(t *testing.T) {
+	counts := map[int]int{}
+	for seed := 0; seed < 256; seed++ {
+		a := WanderAngular(uint8(seed))
+		if a < -2*WanderStep || a > 2*WanderStep || a%WanderStep != 0 {
+			t.Fatalf("WanderAngular(%d) = %d, want a multiple of WanderStep within ±2 steps", seed, a)
+		}
+		counts[a]++
+	}
+	if len(counts) != 5 {
+		t.Errorf("WanderAngular produced %d distinct rates, want 5", len(counts))
+	}
+	if counts[0] <= counts[WanderStep] {
+		t.Errorf("straight (%d) should be more likely than a turn (%d)", counts[0], counts[WanderStep])
+	}
+}
@@ -0,0 +1,66 @@
+package navlogic
+
+const (
+	EchoTimeoutUs  = 30000
+	EchoStuckUs    = 250000
+	PingIntervalUs = 60000
+)
+
+// EchoTimer times HC-SR04 pings from echo pin edges so the caller never waits for one.
+// Edge is meant to run in the echo pin's interrupt handler; callers on an 8-bit target must
+// disable interrupts around Poll and Trigger, since the timestamps are not written atomically.
+// Times are microsecond ticks that may wrap.
+type EchoTimer struct {
+	triggeredUs uint32
+	riseUs      uint32
+	pulseUs     uint32
+	started     bool
+	pinging     bool
+	rising      bool
+	done        bool
+	reading     RangeReading
+}
+
+// Trigger records that a ping was sent at nowUs.
+func (t *EchoTimer) Trigger(nowUs uint32) {
+	t.triggeredUs = nowUs
+	t.started, t.pinging, t.done = true, true, false
+}
+
+// Edge records a change of the echo pin: the pulse starts when it goes high and is measured when it goes low.
+// The pin level is kept across pings, so an echo that stays high is reported as stuck, not as missing.
+func (t *EchoTimer) Edge(high bool, nowUs uint32) {
+	switch {
+	case high:
+		t.riseUs, t.rising = nowUs, true
+	case t.rising:
+		t.pulseUs, t.rising, t.done = nowUs-t.riseUs, false, true
+	}
+}
+
+// Poll finishes the current ping once its echo has ended, the echo never rose within EchoTimeoutUs,
+// or it stayed high for EchoStuckUs, and returns the latest reading (out of range before the first result).
+// due reports that the next ping should be sent: at the first call, then PingIntervalUs after the previous one.
+func (t *EchoTimer) Poll(nowUs uint32) (reading RangeReading, due bool) {
+	if !t.started {
+		t.reading = RangeFromCm(TimeoutDistance)
+		return t.reading, true
+	}
+	if t.pinging {
+		switch {
+		case t.done && t.pulseUs <= EchoTimeoutUs:
+			t.finish(RangeReading{Cm: EchoMicrosecondsToDistanceCm(int(t.pulseUs))})
+		case t.done:
+			t.finish(RangeReading{Cm: TimeoutDistance, Status: RangeOutOfRange})
+		case t.rising && nowUs-t.riseUs > EchoStuckUs:
+			t.finish(RangeReading{Cm: TimeoutDistance, Status: RangeStuckHigh})
+		case !t.rising && nowUs-t.triggeredUs > EchoTimeoutUs:
+			t.finish(RangeReading{Cm: TimeoutDistance, Status: RangeNoEcho})
+		}
+	}
+	return t.reading, !t.pinging && nowUs-t.triggeredUs >= PingIntervalUs
+}
+
+func (t *EchoTimer) finish(r RangeReading) {
+	t.reading, t.pinging = r, false
+}
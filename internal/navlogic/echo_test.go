@@ -0,0 +1,71 @@
+package navlogic
+
+import "testing"
+
+func TestEchoTimer(t *testing.T) {
+	type event struct {
+		atUs       uint32
+		kind       byte // 't' trigger, 'h' echo high, 'l' echo low, 'p' poll
+		wantCm     int
+		wantStatus int
+		wantDue    bool
+	}
+	const none = TimeoutDistance
+	tests := []struct {
+		name   string
+		events []event
+	}{
+		{"first poll asks for a ping", []event{{0, 'p', none, RangeOutOfRange, true}}},
+		{"measured echo", []event{
+			{0, 't', 0, 0, false}, {400, 'h', 0, 0, false}, {400 + 1160, 'l', 0, 0, false},
+			{2000, 'p', 20, RangeOK, false},
+		}},
+		{"ping in flight keeps the previous result", []event{
+			{0, 't', 0, 0, false}, {100, 'h', 0, 0, false}, {100 + 5800, 'l', 0, 0, false},
+			{60000, 'p', 100, RangeOK, true}, {60000, 't', 0, 0, false}, {60100, 'h', 0, 0, false},
+			{61000, 'p', 100, RangeOK, false},
+		}},
+		{"echo never rises", []event{
+			{0, 't', 0, 0, false}, {EchoTimeoutUs + 1, 'p', none, RangeNoEcho, false},
+			{PingIntervalUs, 'p', none, RangeNoEcho, true},
+		}},
+		{"long echo is out of range", []event{
+			{0, 't', 0, 0, false}, {200, 'h', 0, 0, false}, {EchoTimeoutUs + 1000, 'p', none, RangeOutOfRange, false},
+			{38200, 'l', 0, 0, false}, {40000, 'p', none, RangeOutOfRange, false},
+		}},
+		{"echo stuck high", []event{
+			{0, 't', 0, 0, false}, {200, 'h', 0, 0, false}, {100000, 'p', none, RangeOutOfRange, false},
+			{EchoStuckUs + 201, 'p', none, RangeStuckHigh, true},
+			{EchoStuckUs + 201, 't', 0, 0, false}, {EchoStuckUs + 300, 'p', none, RangeStuckHigh, false},
+		}},
+		{"falling edge without a rise is ignored", []event{
+			{0, 't', 0, 0, false}, {500, 'l', 0, 0, false}, {EchoTimeoutUs + 1, 'p', none, RangeNoEcho, false},
+		}},
+		{"microsecond counter wraps", []event{
+			{0xFFFFFF00, 't', 0, 0, false}, {0xFFFFFFF0, 'h', 0, 0, false}, {580 - 0x10, 'l', 0, 0, false},
+			{1000, 'p', 10, RangeOK, false},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var et EchoTimer
+			if tt.events[0].kind != 'p' {
+				et.Poll(tt.events[0].atUs)
+			}
+			for i, e := range tt.events {
+				switch e.kind {
+				case 't':
+					et.Trigger(e.atUs)
+				case 'h', 'l':
+					et.Edge(e.kind == 'h', e.atUs)
+				case 'p':
+					got, due := et.Poll(e.atUs)
+					want := RangeReading{Cm: e.wantCm, Status: e.wantStatus}
+					if got != want || due != e.wantDue {
+						t.Fatalf("event %d: Poll(%d) = (%+v, %v), want (%+v, %v)", i, e.atUs, got, due, want, e.wantDue)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package navlogic
+
+// Edge escape turns returned by DecideEdgeTurn.
+const (
+	EdgeTurnNone   = iota // no front edge
+	EdgeTurnLeft          // edge under the right sensor: turn left, away from it
+	EdgeTurnRight         // edge under the left sensor: turn right, away from it
+	EdgeTurnAround        // edge under both sensors (head-on or a corner): back up further and turn 180°
+)
+
+// DecideEdgeTurn picks which way to turn away from a desk edge from the front IR sensors that fired.
+func DecideEdgeTurn(frontLeft, frontRight bool) int {
+	switch {
+	case frontLeft && frontRight:
+		return EdgeTurnAround
+	case frontLeft:
+		return EdgeTurnRight
+	case frontRight:
+		return EdgeTurnLeft
+	default:
+		return EdgeTurnNone
+	}
+}
@@ -0,0 +1,25 @@
+package navlogic
+
+import "testing"
+
+func TestDecideEdgeTurn(t *testing.T) {
+	tests := []struct {
+		name       string
+		frontLeft  bool
+		frontRight bool
+		want       int
+	}{
+		{"no edge", false, false, EdgeTurnNone},
+		{"left sensor turns right", true, false, EdgeTurnRight},
+		{"right sensor turns left", false, true, EdgeTurnLeft},
+		{"both sensors turn around", true, true, EdgeTurnAround},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DecideEdgeTurn(tt.frontLeft, tt.frontRight)
+			if got != tt.want {
+				t.Errorf("DecideEdgeTurn(%v, %v) = %d, want %d", tt.frontLeft, tt.frontRight, got, tt.want)
+			}
+		})
+	}
+}
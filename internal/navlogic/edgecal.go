@@ -0,0 +1,42 @@
+package navlogic
+
+const (
+	EdgeCalSamples        = 16
+	EdgeMarginPercent     = 50
+	EdgeMinSurfaceReading = 1000
+	EdgeMaxSpreadPercent  = 40
+)
+
+// Edge sensor calibration results from CalibrateEdgeSensor.
+const (
+	EdgeSensorOK           = iota
+	EdgeSensorOverEdge     // reads too low to be on the surface: already over an edge, or shorted to ground
+	EdgeSensorDisconnected // readings jump around: a floating, unconnected input
+)
+
+// CalibrateEdgeSensor derives an edge threshold from raw readings taken on the table surface:
+// EdgeMarginPercent below their mean. A sensor that fails returns fallback with its status.
+func CalibrateEdgeSensor(samples []uint16, fallback uint16) (threshold uint16, status int) {
+	if len(samples) == 0 {
+		return fallback, EdgeSensorDisconnected
+	}
+	var sum uint32
+	lo, hi := samples[0], samples[0]
+	for _, v := range samples {
+		sum += uint32(v)
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	mean := sum / uint32(len(samples))
+	if mean < EdgeMinSurfaceReading {
+		return fallback, EdgeSensorOverEdge
+	}
+	if uint32(hi-lo)*100 > mean*EdgeMaxSpreadPercent {
+		return fallback, EdgeSensorDisconnected
+	}
+	return uint16(mean * (100 - EdgeMarginPercent) / 100), EdgeSensorOK
+}
@@ -0,0 +1,29 @@
+package navlogic
+
+import "testing"
+
+func TestCalibrateEdgeSensor(t *testing.T) {
+	const fallback = 500
+	tests := []struct {
+		name       string
+		samples    []uint16
+		want       uint16
+		wantStatus int
+	}{
+		{"steady surface", []uint16{40000, 40000, 40000, 40000}, 20000, EdgeSensorOK},
+		{"surface with noise", []uint16{38000, 42000, 40000, 40000}, 20000, EdgeSensorOK},
+		{"full scale surface", []uint16{0xFFFF, 0xFFFF}, 32767, EdgeSensorOK},
+		{"dim surface just above minimum", []uint16{1000, 1000}, 500, EdgeSensorOK},
+		{"over an edge", []uint16{30, 10, 20, 40}, fallback, EdgeSensorOverEdge},
+		{"floating input", []uint16{5000, 30000, 12000, 60000}, fallback, EdgeSensorDisconnected},
+		{"no samples", nil, fallback, EdgeSensorDisconnected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, status := CalibrateEdgeSensor(tt.samples, fallback)
+			if got != tt.want || status != tt.wantStatus {
+				t.Errorf("CalibrateEdgeSensor(%v) = (%d, %d), want (%d, %d)", tt.samples, got, status, tt.want, tt.wantStatus)
+			}
+		})
+	}
+}
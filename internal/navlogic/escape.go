@@ -0,0 +1,33 @@
+package navlogic
+
+// Escape manoeuvres returned by DecideEscape.
+const (
+	EscapeContinue   = iota // keep the current manoeuvre
+	EscapeReverseArc        // front edge: reverse away, then arc forward to turn
+	EscapeForwardArc        // rear edge while reversing: abort the reverse and arc forward
+	EscapeSpin              // edges front and rear: turn in place without moving either way
+)
+
+// DecideEscape picks a safe manoeuvre from the front and rear edge readings and the sign of
+// the current linear speed (negative while reversing, zero while turning in place).
+func DecideEscape(frontEdge, rearEdge bool, linear int) int {
+	switch {
+	case frontEdge && rearEdge:
+		if linear == 0 {
+			return EscapeContinue
+		}
+		return EscapeSpin
+	case frontEdge:
+		if linear < 0 {
+			return EscapeContinue
+		}
+		return EscapeReverseArc
+	case rearEdge:
+		if linear < 0 {
+			return EscapeForwardArc
+		}
+		return EscapeContinue
+	default:
+		return EscapeContinue
+	}
+}
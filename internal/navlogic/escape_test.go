@@ -0,0 +1,33 @@
+package navlogic
+
+import "testing"
+
+func TestDecideEscape(t *testing.T) {
+	tests := []struct {
+		name      string
+		frontEdge bool
+		rearEdge  bool
+		linear    int
+		want      int
+	}{
+		{"clear while driving", false, false, 80, EscapeContinue},
+		{"clear while reversing", false, false, -80, EscapeContinue},
+		{"front edge while driving", true, false, 80, EscapeReverseArc},
+		{"front edge while spinning", true, false, 0, EscapeReverseArc},
+		{"front edge while already reversing", true, false, -80, EscapeContinue},
+		{"rear edge while reversing", false, true, -80, EscapeForwardArc},
+		{"rear edge while driving away", false, true, 20, EscapeContinue},
+		{"rear edge while spinning", false, true, 0, EscapeContinue},
+		{"both edges while reversing", true, true, -80, EscapeSpin},
+		{"both edges while driving", true, true, 20, EscapeSpin},
+		{"both edges while spinning", true, true, 0, EscapeContinue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DecideEscape(tt.frontEdge, tt.rearEdge, tt.linear)
+			if got != tt.want {
+				t.Errorf("DecideEscape(%v, %v, %d) = %d, want %d", tt.frontEdge, tt.rearEdge, tt.linear, got, tt.want)
+			}
+		})
+	}
+}
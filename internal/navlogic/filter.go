@@ -0,0 +1,100 @@
+package navlogic
+
+const (
+	DistanceMedianWindow     = 3
+	DistanceSmoothingPercent = 50
+	DistanceMaxJumpCm        = 30
+	DistanceFarCm            = 400
+	ObstacleHysteresisCm     = 5
+)
+
+// distanceScale is the fixed-point scale of the smoothed estimate, so small steps are not truncated away.
+const distanceScale = 16
+
+// DistanceFilter cleans up raw ultrasonic readings without allocating. A reading more than
+// DistanceMaxJumpCm from the estimate is held back until the next reading confirms it, so a single
+// spurious echo is dropped while a real change (a hand, a turn towards a wall) costs one reading.
+// Accepted readings go through a median of DistanceMedianWindow, then exponential smoothing.
+// The zero value is ready to use.
+type DistanceFilter struct {
+	window     [DistanceMedianWindow]int
+	n, next    int
+	estimate   int // cm * distanceScale
+	candidate  int
+	hasPending bool
+}
+
+// Update feeds one raw reading (cm, or TimeoutDistance) and returns the filtered distance.
+// Timeouts count as DistanceFarCm; a filtered distance at or beyond it is returned as TimeoutDistance.
+func (f *DistanceFilter) Update(raw int) int {
+	if raw == TimeoutDistance || raw > DistanceFarCm {
+		raw = DistanceFarCm
+	}
+	switch {
+	case f.n == 0:
+		f.snap(raw)
+	case abs(raw*distanceScale-f.estimate) > DistanceMaxJumpCm*distanceScale:
+		if f.hasPending && abs(raw-f.candidate) <= DistanceMaxJumpCm {
+			f.snap(raw)
+		} else {
+			f.candidate, f.hasPending = raw, true
+		}
+	default:
+		f.hasPending = false
+		f.window[f.next] = raw
+		f.next = (f.next + 1) % DistanceMedianWindow
+		if f.n < DistanceMedianWindow {
+			f.n++
+		}
+		f.estimate += (f.median()*distanceScale - f.estimate) * DistanceSmoothingPercent / 100
+	}
+	return f.Distance()
+}
+
+// Distance returns the current filtered distance in cm, or TimeoutDistance if nothing is in range.
+func (f *DistanceFilter) Distance() int {
+	if f.n == 0 {
+		return TimeoutDistance
+	}
+	d := (f.estimate + distanceScale/2) / distanceScale
+	if d >= DistanceFarCm {
+		return TimeoutDistance
+	}
+	return d
+}
+
+func (f *DistanceFilter) Reset() {
+	*f = DistanceFilter{}
+}
+
+// snap restarts the filter at a confirmed reading.
+func (f *DistanceFilter) snap(raw int) {
+	f.window[0], f.n, f.next = raw, 1, 1
+	f.estimate = raw * distanceScale
+	f.hasPending = false
+}
+
+func (f *DistanceFilter) median() int {
+	var sorted [DistanceMedianWindow]int
+	copy(sorted[:], f.window[:f.n])
+	s := sorted[:f.n]
+	for i := 1; i < len(s); i++ {
+		for j := i; j > 0 && s[j] < s[j-1]; j-- {
+			s[j], s[j-1] = s[j-1], s[j]
+		}
+	}
+	if len(s)%2 == 0 {
+		return (s[len(s)/2-1] + s[len(s)/2]) / 2
+	}
+	return s[len(s)/2]
+}
+
+// IsObstacleWithHysteresis reports whether distance is an obstacle given the previous result:
+// an obstacle is entered below threshold and only cleared at threshold+ObstacleHysteresisCm or a timeout,
+// so readings hovering at the boundary do not flicker between states.
+func IsObstacleWithHysteresis(distance, threshold int, wasObstacle bool) bool {
+	if wasObstacle {
+		threshold += ObstacleHysteresisCm
+	}
+	return IsWithinThreshold(distance, threshold)
+}
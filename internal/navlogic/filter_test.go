@@ -0,0 +1,84 @@
+package navlogic
+
+import "testing"
+
+func TestDistanceFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  []int
+		want []int
+	}{
+		{"first reading is taken as is", []int{50}, []int{50}},
+		{"steady", []int{50, 50, 50}, []int{50, 50, 50}},
+		{"noise is smoothed", []int{50, 54, 46, 52, 48}, []int{50, 51, 51, 51, 50}},
+		{"single spike dropped", []int{50, 50, 3, 50}, []int{50, 50, 50, 50}},
+		{"single dropout dropped", []int{50, 50, TimeoutDistance, 50}, []int{50, 50, 50, 50}},
+		{"confirmed jump snaps", []int{50, 50, 10, 11}, []int{50, 50, 50, 11}},
+		{"approach tracks", []int{40, 38, 36, 34, 32}, []int{40, 40, 39, 37, 36}},
+		{"nothing in range", []int{TimeoutDistance, TimeoutDistance}, []int{TimeoutDistance, TimeoutDistance}},
+		{"out of range reads as timeout", []int{600}, []int{TimeoutDistance}},
+		{"object leaves", []int{20, 20, TimeoutDistance, TimeoutDistance}, []int{20, 20, 20, TimeoutDistance}},
+		{"two different spikes both dropped", []int{50, 5, 200, 50}, []int{50, 50, 50, 50}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f DistanceFilter
+			for i, raw := range tt.raw {
+				if got := f.Update(raw); got != tt.want[i] {
+					t.Fatalf("reading %d: Update(%d) = %d, want %d (sequence %v)", i, raw, got, tt.want[i], tt.raw)
+				}
+			}
+		})
+	}
+}
+
+func TestDistanceFilter_Reset(t *testing.T) {
+	var f DistanceFilter
+	f.Update(50)
+	f.Reset()
+	if got := f.Distance(); got != TimeoutDistance {
+		t.Errorf("Distance after Reset = %d, want %d", got, TimeoutDistance)
+	}
+	if got := f.Update(10); got != 10 {
+		t.Errorf("first Update after Reset = %d, want 10", got)
+	}
+}
+
+func TestIsObstacleWithHysteresis(t *testing.T) {
+	const threshold = 20
+	tests := []struct {
+		name        string
+		distance    int
+		wasObstacle bool
+		want        bool
+	}{
+		{"enters below threshold", 19, false, true},
+		{"not entered at threshold", 20, false, false},
+		{"held inside band", 22, true, true},
+		{"cleared at band edge", threshold + ObstacleHysteresisCm, true, false},
+		{"cleared by timeout", TimeoutDistance, true, false},
+		{"band does not enter", 22, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsObstacleWithHysteresis(tt.distance, threshold, tt.wasObstacle); got != tt.want {
+				t.Errorf("IsObstacleWithHysteresis(%d, %d, %v) = %v, want %v", tt.distance, threshold, tt.wasObstacle, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistanceFilter_FlickerAtThreshold(t *testing.T) {
+	var f DistanceFilter
+	obstacle, changes := false, 0
+	for _, raw := range []int{24, 18, 23, 19, 22, 18, 24, 19, 23} {
+		next := IsObstacleWithHysteresis(f.Update(raw), 20, obstacle)
+		if next != obstacle {
+			changes++
+		}
+		obstacle = next
+	}
+	if changes > 1 {
+		t.Errorf("obstacle state changed %d times for readings hovering at the threshold, want at most 1", changes)
+	}
+}
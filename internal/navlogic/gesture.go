@@ -0,0 +1,50 @@
+package navlogic
+
+const (
+	GestureNearCm   = 10
+	GestureFarCm    = 20
+	GestureMinWaves = 2
+	GestureWindow   = 12
+	InteractTicks   = 30
+)
+
+// DistanceHistory keeps the last GestureWindow distance readings, oldest first, without allocating.
+type DistanceHistory struct {
+	buf [GestureWindow]int
+	n   int
+}
+
+func (h *DistanceHistory) Push(distance int) {
+	copy(h.buf[:], h.buf[1:])
+	h.buf[GestureWindow-1] = distance
+	if h.n < GestureWindow {
+		h.n++
+	}
+}
+
+func (h *DistanceHistory) Reset() {
+	h.n = 0
+}
+
+// Samples returns the stored readings, oldest first; the slice aliases the history.
+func (h *DistanceHistory) Samples() []int {
+	return h.buf[GestureWindow-h.n:]
+}
+
+// IsPetGesture reports whether history (oldest first) shows a hand waved at the sensor:
+// at least GestureMinWaves near-then-far swings, near being <= GestureNearCm and far >= GestureFarCm or a timeout.
+// Readings in between are ignored, so a hand hovering at mid range does not count.
+func IsPetGesture(history []int) bool {
+	waves := 0
+	near := false
+	for _, d := range history {
+		switch {
+		case d != TimeoutDistance && d <= GestureNearCm:
+			near = true
+		case near && (d == TimeoutDistance || d >= GestureFarCm):
+			waves++
+			near = false
+		}
+	}
+	return waves >= GestureMinWaves
+}
@@ -0,0 +1,54 @@
+package navlogic
+
+import "testing"
+
+func TestIsPetGesture(t *testing.T) {
+	const T = TimeoutDistance
+	tests := []struct {
+		name    string
+		history []int
+		want    bool
+	}{
+		{"empty", nil, false},
+		{"open room", []int{T, T, T, T, T, T}, false},
+		{"single wave", []int{50, 5, 50, 50}, false},
+		{"two waves", []int{50, 5, 50, 6, 60}, true},
+		{"two waves into timeout", []int{T, 4, T, 3, T}, true},
+		{"three quick waves", []int{8, 30, 8, 30, 8, 30}, true},
+		{"hand still near at the end", []int{50, 5, 50, 5, 5}, false},
+		{"hovering at mid range", []int{15, 12, 15, 12, 15, 12}, false},
+		{"mid range does not end a wave", []int{5, 15, 5, 15, 5}, false},
+		{"near exactly at threshold", []int{GestureNearCm, GestureFarCm, GestureNearCm, GestureFarCm}, true},
+		{"far just below threshold", []int{5, GestureFarCm - 1, 5, GestureFarCm - 1}, false},
+		{"wall approaching steadily", []int{40, 30, 20, 15, 10, 8, 6}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsPetGesture(tt.history)
+			if got != tt.want {
+				t.Errorf("IsPetGesture(%v) = %v, want %v", tt.history, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistanceHistory(t *testing.T) {
+	var h DistanceHistory
+	if got := len(h.Samples()); got != 0 {
+		t.Fatalf("empty history has %d samples", got)
+	}
+
+	for i := 1; i <= GestureWindow+3; i++ {
+		h.Push(i)
+	}
+	got := h.Samples()
+	if len(got) != GestureWindow || got[0] != 4 || got[GestureWindow-1] != GestureWindow+3 {
+		t.Errorf("Samples() = %v, want the last %d readings oldest first", got, GestureWindow)
+	}
+
+	h.Reset()
+	h.Push(7)
+	if got := h.Samples(); len(got) != 1 || got[0] != 7 {
+		t.Errorf("Samples() after Reset+Push = %v, want [7]", got)
+	}
+}
@@ -0,0 +1,64 @@
+package navlogic
+
+const (
+	GuardLearnSamples = 10
+	GuardOpenRangeCm  = 400
+	GuardMinDropCm    = 10
+	GuardDropPercent  = 30
+	GuardAlertTicks   = 30
+)
+
+// GuardBaseline is the resting range learned while guarding; alerts are armed once Samples reaches GuardLearnSamples.
+type GuardBaseline struct {
+	DistanceCm int
+	Samples    int
+}
+
+func guardRange(distance int) int {
+	if distance == TimeoutDistance || distance > GuardOpenRangeCm {
+		return GuardOpenRangeCm
+	}
+	return distance
+}
+
+// LearnGuardBaseline folds a reading into the baseline: a running mean while learning, then a 1/8 exponential average.
+// A timeout counts as open range (GuardOpenRangeCm).
+func LearnGuardBaseline(b GuardBaseline, distance int) GuardBaseline {
+	d := guardRange(distance)
+	if b.Samples < GuardLearnSamples {
+		b.DistanceCm = (b.DistanceCm*b.Samples + d) / (b.Samples + 1)
+		b.Samples++
+		return b
+	}
+	b.DistanceCm += (d - b.DistanceCm) / 8
+	return b
+}
+
+// IsGuardIntrusion reports whether distance dropped sharply below an armed baseline:
+// by at least GuardMinDropCm and GuardDropPercent of the baseline.
+func IsGuardIntrusion(b GuardBaseline, distance int) bool {
+	if b.Samples < GuardLearnSamples {
+		return false
+	}
+	drop := b.DistanceCm - guardRange(distance)
+	return drop >= GuardMinDropCm && drop*100 >= b.DistanceCm*GuardDropPercent
+}
+
+// NextGuardState returns the next guard-mode state. Any other state starts guarding;
+// an intrusion raises StateAlert, which returns to StateGuarding after GuardAlertTicks.
+func NextGuardState(currentState int, intrusion bool, alertTicks int) int {
+	switch currentState {
+	case StateGuarding:
+		if intrusion {
+			return StateAlert
+		}
+		return StateGuarding
+	case StateAlert:
+		if alertTicks >= GuardAlertTicks {
+			return StateGuarding
+		}
+		return StateAlert
+	default:
+		return StateGuarding
+	}
+}
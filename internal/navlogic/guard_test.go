@@ -0,0 +1,92 @@
+package navlogic
+
+import "testing"
+
+func learned(distance int) GuardBaseline {
+	var b GuardBaseline
+	for i := 0; i < GuardLearnSamples; i++ {
+		b = LearnGuardBaseline(b, distance)
+	}
+	return b
+}
+
+func TestLearnGuardBaseline(t *testing.T) {
+	tests := []struct {
+		name     string
+		readings []int
+		want     GuardBaseline
+	}{
+		{"first sample", []int{100}, GuardBaseline{100, 1}},
+		{"running mean while learning", []int{100, 80, 60}, GuardBaseline{80, 3}},
+		{"timeout counts as open range", []int{TimeoutDistance}, GuardBaseline{GuardOpenRangeCm, 1}},
+		{"beyond open range is clamped", []int{900}, GuardBaseline{GuardOpenRangeCm, 1}},
+		{"armed baseline moves 1/8 toward reading",
+			[]int{100, 100, 100, 100, 100, 100, 100, 100, 100, 100, 20}, GuardBaseline{90, GuardLearnSamples}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got GuardBaseline
+			for _, d := range tt.readings {
+				got = LearnGuardBaseline(got, d)
+			}
+			if got != tt.want {
+				t.Errorf("LearnGuardBaseline(%v) = %+v, want %+v", tt.readings, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsGuardIntrusion(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseline GuardBaseline
+		distance int
+		want     bool
+	}{
+		{"still learning", GuardBaseline{100, GuardLearnSamples - 1}, 10, false},
+		{"steady reading", learned(100), 100, false},
+		{"small drift", learned(100), 92, false},
+		{"sharp drop", learned(100), 50, true},
+		{"drop exactly 30%", learned(100), 70, true},
+		{"drop just under 30%", learned(100), 71, false},
+		{"short baseline needs 10 cm", learned(20), 12, false},
+		{"short baseline 10 cm drop", learned(20), 10, true},
+		{"approach from open room", learned(TimeoutDistance), 120, true},
+		{"timeout in open room", learned(TimeoutDistance), TimeoutDistance, false},
+		{"timeout on short baseline is not an intrusion", learned(50), TimeoutDistance, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsGuardIntrusion(tt.baseline, tt.distance)
+			if got != tt.want {
+				t.Errorf("IsGuardIntrusion(%+v, %d) = %v, want %v", tt.baseline, tt.distance, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextGuardState(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    int
+		intrusion  bool
+		alertTicks int
+		want       int
+	}{
+		{"idle starts guarding", StateIdle, false, 0, StateGuarding},
+		{"moving starts guarding even on intrusion", StateMoving, true, 0, StateGuarding},
+		{"guarding stays quiet", StateGuarding, false, 0, StateGuarding},
+		{"guarding raises alert", StateGuarding, true, 0, StateAlert},
+		{"alert holds", StateAlert, false, GuardAlertTicks - 1, StateAlert},
+		{"alert holds on intrusion", StateAlert, true, 1, StateAlert},
+		{"alert expires", StateAlert, true, GuardAlertTicks, StateGuarding},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NextGuardState(tt.current, tt.intrusion, tt.alertTicks)
+			if got != tt.want {
+				t.Errorf("NextGuardState(%d, %v, %d) = %d, want %d", tt.current, tt.intrusion, tt.alertTicks, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package navlogic
+
+const (
+	WholeNoteMs         = 1600
+	DefaultNoteDuration = 8
+	DefaultOctave       = 6
+	NoteGapMs           = 10
+)
+
+// octave8Hz holds the frequencies of C8..B8; lower octaves halve them.
+var octave8Hz = [12]uint16{4186, 4435, 4699, 4978, 5274, 5588, 5920, 6272, 6645, 7040, 7459, 7902}
+
+// semitones maps note letters a..g to their semitone above C.
+var semitones = [7]uint8{9, 11, 0, 2, 4, 5, 7}
+
+// Note is one melody note; Hz 0 is a rest.
+type Note struct {
+	Hz uint16
+	Ms uint32
+}
+
+// NextNote parses the note at melody[pos:] and returns it with the position of the next one; ok is false
+// at the end of the melody or at a malformed note. Melodies are comma-separated notes in RTTTL note syntax,
+// without the header: [duration]note[#][.][octave], e.g. "16e6,16g6,8c7,4p,2c#.5". The duration is a
+// fraction of WholeNoteMs (1, 2, 4, 8, 16 or 32; default DefaultNoteDuration), the note is a..g or p for a rest,
+// '.' makes it half as long again and the octave is 4..8 (default DefaultOctave). Parsing does not allocate.
+func NextNote(melody string, pos int) (note Note, next int, ok bool) {
+	for pos < len(melody) && (melody[pos] == ' ' || melody[pos] == ',') {
+		pos++
+	}
+	if pos >= len(melody) {
+		return Note{}, pos, false
+	}
+
+	duration := 0
+	for pos < len(melody) && melody[pos] >= '0' && melody[pos] <= '9' {
+		duration = duration*10 + int(melody[pos]-'0')
+		pos++
+	}
+	if duration == 0 {
+		duration = DefaultNoteDuration
+	}
+	if duration > 32 || 32%duration != 0 || pos >= len(melody) {
+		return Note{}, pos, false
+	}
+
+	letter := melody[pos] | 0x20
+	pos++
+	rest := letter == 'p'
+	if !rest && (letter < 'a' || letter > 'g') {
+		return Note{}, pos, false
+	}
+	semitone := 0
+	if !rest {
+		semitone = int(semitones[letter-'a'])
+	}
+	if pos < len(melody) && melody[pos] == '#' {
+		semitone++
+		pos++
+	}
+
+	dotted := false
+	octave := DefaultOctave
+	for ; pos < len(melody) && melody[pos] != ','; pos++ {
+		switch c := melody[pos]; {
+		case c == '.':
+			dotted = true
+		case c >= '4' && c <= '8':
+			octave = int(c - '0')
+		case c != ' ':
+			return Note{}, pos, false
+		}
+	}
+
+	note.Ms = uint32(WholeNoteMs / duration)
+	if dotted {
+		note.Ms += note.Ms / 2
+	}
+	if !rest {
+		if semitone == 12 {
+			semitone, octave = 0, octave+1
+		}
+		if octave > 8 {
+			return Note{}, pos, false
+		}
+		note.Hz = octave8Hz[semitone] >> (8 - octave)
+	}
+	return note, pos, true
+}
+
+// MelodyPlayer plays a melody over time without blocking; the main loop advances it by the elapsed time.
+type MelodyPlayer struct {
+	melody    string
+	next      int
+	note      Note
+	active    bool
+	started   bool
+	elapsedMs uint32
+}
+
+// Start replaces the playing melody. Like MotionPlan, time counts from the first Advance.
+func (p *MelodyPlayer) Start(melody string) {
+	p.melody, p.next = melody, 0
+	p.elapsedMs, p.started = 0, false
+	p.active = p.load()
+}
+
+func (p *MelodyPlayer) Stop() {
+	p.active = false
+}
+
+func (p *MelodyPlayer) Active() bool {
+	return p.active
+}
+
+// Advance moves the melody on by elapsedMs and returns the pitch to sound now: 0 for rests,
+// the last NoteGapMs of every note, and once the melody has finished.
+func (p *MelodyPlayer) Advance(elapsedMs uint32) uint16 {
+	if !p.active {
+		return 0
+	}
+	if !p.started {
+		p.started = true
+	} else {
+		p.elapsedMs += elapsedMs
+	}
+	for p.elapsedMs >= p.note.Ms {
+		p.elapsedMs -= p.note.Ms
+		if !p.load() {
+			p.active = false
+			return 0
+		}
+	}
+	if p.elapsedMs+NoteGapMs >= p.note.Ms {
+		return 0
+	}
+	return p.note.Hz
+}
+
+func (p *MelodyPlayer) load() bool {
+	note, next, ok := NextNote(p.melody, p.next)
+	p.note, p.next = note, next
+	return ok
+}
@@ -0,0 +1,88 @@
+package navlogic
+
+import "testing"
+
+func TestNextNote(t *testing.T) {
+	tests := []struct {
+		name   string
+		melody string
+		want   []Note
+	}{
+		{"empty", "", nil},
+		{"defaults", "a", []Note{{1760, 200}}},
+		{"durations and octaves", "4c4,16a5,1g8", []Note{{261, 400}, {880, 100}, {6272, 1600}}},
+		{"sharp", "8c#7", []Note{{2217, 200}}},
+		{"dotted before or after the octave", "4e.6,4e6.", []Note{{1318, 600}, {1318, 600}}},
+		{"rest", "16p,2p.", []Note{{0, 100}, {0, 1200}}},
+		{"b sharp is the next c", "b#5", []Note{{1046, 200}}},
+		{"spaces and upper case", " 16E6, 16G6 ,8C7", []Note{{1318, 100}, {1568, 100}, {2093, 200}}},
+		{"stops at a bad note", "8c6,8x6,8c6", []Note{{1046, 200}}},
+		{"stops at a bad duration", "8c6,3c6", []Note{{1046, 200}}},
+		{"stops at an octave too high", "8c6,8b#8", []Note{{1046, 200}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Note
+			for pos := 0; ; {
+				note, next, ok := NextNote(tt.melody, pos)
+				if !ok {
+					break
+				}
+				got = append(got, note)
+				pos = next
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("NextNote(%q) gave %v, want %v", tt.melody, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("note %d of %q = %+v, want %+v", i, tt.melody, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNextNote_Allocations(t *testing.T) {
+	allocs := testing.AllocsPerRun(10, func() {
+		for pos := 0; ; {
+			_, next, ok := NextNote("16e6,16g6,8c7,4p,2c#.5", pos)
+			if !ok {
+				break
+			}
+			pos = next
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("NextNote allocated %v times per melody, want 0", allocs)
+	}
+}
+
+func TestMelodyPlayer(t *testing.T) {
+	tests := []struct {
+		name   string
+		melody string
+		stepMs uint32
+		want   []uint16
+	}{
+		{"notes with gaps", "16c7,16e7", 50, []uint16{2093, 2093, 2637, 2637, 0, 0}},
+		{"last gap of a note is silent", "16c7", 95, []uint16{2093, 0, 0}},
+		{"rest", "32c7,32p,32c7", 50, []uint16{2093, 0, 2093, 0}},
+		{"long step skips notes", "32c7,32d7,32e7,8g7", 150, []uint16{2093, 3136, 3136, 0}},
+		{"empty melody is silent", "", 50, []uint16{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p MelodyPlayer
+			p.Start(tt.melody)
+			for i, want := range tt.want {
+				if got := p.Advance(tt.stepMs); got != want {
+					t.Fatalf("step %d (every %d ms): %d Hz, want %d", i, tt.stepMs, got, want)
+				}
+			}
+			if p.Active() {
+				t.Error("still active after the melody")
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package navlogic
+
+const (
+	MoodMax          = 100
+	MoodBaseline     = 50
+	MoodStepMs       = 1000
+	EnergyDrainSteps = 3
+)
+
+// Mood events, fed to Mood.React when the pet meets them.
+const (
+	MoodEventObstacle = iota
+	MoodEventEdgeScare
+	MoodEventInteraction
+	MoodEventIntruder
+)
+
+// Feelings, the dominant emotion Mood.Feeling picks from the mood values.
+const (
+	FeelingContent = iota
+	FeelingHappy
+	FeelingAfraid
+	FeelingBored
+	FeelingTired
+)
+
+// Feeling thresholds on the 0..MoodMax mood values.
+const (
+	AfraidFear     = 60
+	TiredEnergy    = 20
+	BoredBoredom   = 70
+	HappyHappiness = 70
+)
+
+// Per-step drift and per-event changes of the mood values.
+const (
+	FearDecay      = 2
+	HappinessDrift = 1
+	BoredomResting = 2
+	BoredomMoving  = 1
+	EnergyRecovery = 1
+	NoveltyBoredom = 5
+	ObstacleFear   = 10
+	ObstacleJoy    = 2
+	EdgeScareFear  = 30
+	EdgeScareJoy   = 10
+	IntruderFear   = 25
+	IntruderJoy    = 5
+	PlayfulJoy     = 40
+	PlayfulEnergy  = 10
+	PlayfulBoredom = 60
+)
+
+// Share of the cruise speed above CreepSpeed used per feeling; a content or happy pet uses all of it.
+const (
+	AfraidSpeedPct = 60
+	TiredSpeedPct  = 60
+	BoredSpeedPct  = 80
+)
+
+// Mood holds the pet's emotions, each 0..MoodMax. Events push them up or down at once; Advance lets them
+// drift back over time: fear fades, happiness returns to MoodBaseline, boredom builds up (faster at rest),
+// and energy drains while moving and recovers at rest.
+type Mood struct {
+	Happiness int
+	Fear      int
+	Energy    int
+	Boredom   int
+	elapsedMs uint32
+	steps     uint8
+}
+
+func NewMood() Mood {
+	return Mood{Happiness: MoodBaseline, Energy: MoodMax}
+}
+
+func clampMood(v int) int {
+	if v < 0 {
+		return 0
+	}
+	if v > MoodMax {
+		return MoodMax
+	}
+	return v
+}
+
+// React applies an event: scares raise fear and dent happiness, anything new eases boredom,
+// and an interaction cheers the pet up and wakes it a little.
+func (m *Mood) React(event int) {
+	switch event {
+	case MoodEventObstacle:
+		m.Fear += ObstacleFear
+		m.Happiness -= ObstacleJoy
+		m.Boredom -= NoveltyBoredom
+	case MoodEventEdgeScare:
+		m.Fear += EdgeScareFear
+		m.Happiness -= EdgeScareJoy
+		m.Boredom -= NoveltyBoredom
+	case MoodEventInteraction:
+		m.Happiness += PlayfulJoy
+		m.Energy += PlayfulEnergy
+		m.Boredom -= PlayfulBoredom
+	case MoodEventIntruder:
+		m.Fear += IntruderFear
+		m.Happiness -= IntruderJoy
+		m.Boredom -= NoveltyBoredom
+	}
+	m.clamp()
+}
+
+// Advance moves the mood on by elapsedMs, one MoodStepMs step at a time; moving is whether the pet is driving about.
+func (m *Mood) Advance(elapsedMs uint32, moving bool) {
+	m.elapsedMs += elapsedMs
+	for m.elapsedMs >= MoodStepMs {
+		m.elapsedMs -= MoodStepMs
+		m.step(moving)
+	}
+}
+
+func (m *Mood) step(moving bool) {
+	m.Fear -= FearDecay
+	if m.Happiness > MoodBaseline {
+		m.Happiness -= HappinessDrift
+	} else if m.Happiness < MoodBaseline {
+		m.Happiness += HappinessDrift
+	}
+	if moving {
+		m.Boredom += BoredomMoving
+		m.steps++
+		if m.steps >= EnergyDrainSteps {
+			m.steps = 0
+			m.Energy--
+		}
+	} else {
+		m.Boredom += BoredomResting
+		m.Energy += EnergyRecovery
+	}
+	m.clamp()
+}
+
+func (m *Mood) clamp() {
+	m.Happiness = clampMood(m.Happiness)
+	m.Fear = clampMood(m.Fear)
+	m.Energy = clampMood(m.Energy)
+	m.Boredom = clampMood(m.Boredom)
+}
+
+// Rest is a good sleep: the pet wakes full of energy with its fears and boredom gone.
+func (m *Mood) Rest() {
+	m.Energy = MoodMax
+	m.Fear = 0
+	m.Boredom = 0
+}
+
+// Feeling returns the dominant emotion: fear first, then tiredness, boredom and happiness.
+func (m Mood) Feeling() int {
+	switch {
+	case m.Fear >= AfraidFear:
+		return FeelingAfraid
+	case m.Energy <= TiredEnergy:
+		return FeelingTired
+	case m.Boredom >= BoredBoredom:
+		return FeelingBored
+	case m.Happiness >= HappyHappiness:
+		return FeelingHappy
+	default:
+		return FeelingContent
+	}
+}
+
+// FeelingSpeedPercent returns the share of the cruise speed above CreepSpeed to use for feeling.
+func FeelingSpeedPercent(feeling int) int {
+	switch feeling {
+	case FeelingAfraid:
+		return AfraidSpeedPct
+	case FeelingTired:
+		return TiredSpeedPct
+	case FeelingBored:
+		return BoredSpeedPct
+	default:
+		return 100
+	}
+}
+
+// ScaleCruiseSpeed scales the part of speed above CreepSpeed by percent, so a moody pet never stalls.
+func ScaleCruiseSpeed(speed, percent int) int {
+	if speed <= CreepSpeed {
+		return speed
+	}
+	return CreepSpeed + (speed-CreepSpeed)*percent/100
+}
@@ -0,0 +1,162 @@
+package navlogic
+
+import "testing"
+
+func TestMood_React(t *testing.T) {
+	tests := []struct {
+		name   string
+		start  Mood
+		events []int
+		want   Mood
+	}{
+		{"edge scare", NewMood(), []int{MoodEventEdgeScare}, Mood{Happiness: 40, Fear: 30, Energy: 100}},
+		{"repeated edge scares saturate fear", NewMood(), []int{MoodEventEdgeScare, MoodEventEdgeScare, MoodEventEdgeScare, MoodEventEdgeScare},
+			Mood{Happiness: 10, Fear: MoodMax, Energy: 100}},
+		{"obstacle eases boredom", Mood{Happiness: 50, Energy: 100, Boredom: 40}, []int{MoodEventObstacle},
+			Mood{Happiness: 48, Fear: 10, Energy: 100, Boredom: 35}},
+		{"interaction cheers up", Mood{Happiness: 50, Energy: 15, Boredom: 80}, []int{MoodEventInteraction},
+			Mood{Happiness: 90, Energy: 25, Boredom: 20}},
+		{"intruder", NewMood(), []int{MoodEventIntruder}, Mood{Happiness: 45, Fear: 25, Energy: 100}},
+		{"unknown event", NewMood(), []int{99}, NewMood()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.start
+			for _, e := range tt.events {
+				got.React(e)
+			}
+			if got != tt.want {
+				t.Errorf("after %v: %+v, want %+v", tt.events, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMood_Advance(t *testing.T) {
+	tests := []struct {
+		name   string
+		start  Mood
+		ms     uint32
+		moving bool
+		want   Mood
+	}{
+		{"less than a step changes nothing", Mood{Happiness: 90, Fear: 40, Energy: 50}, MoodStepMs - 1, true,
+			Mood{Happiness: 90, Fear: 40, Energy: 50, elapsedMs: MoodStepMs - 1}},
+		{"fear fades and happiness drifts back", Mood{Happiness: 90, Fear: 40, Energy: 100}, 10 * MoodStepMs, true,
+			Mood{Happiness: 80, Fear: 20, Energy: 97, Boredom: 10, steps: 1}},
+		{"sadness drifts back up", Mood{Happiness: 45, Energy: 100}, 10 * MoodStepMs, true,
+			Mood{Happiness: 50, Energy: 97, Boredom: 10, steps: 1}},
+		{"rest recovers energy and bores", Mood{Happiness: 50, Energy: 10}, 20 * MoodStepMs, false,
+			Mood{Happiness: 50, Energy: 30, Boredom: 40}},
+		{"values stay in range", Mood{Happiness: 50, Energy: MoodMax, Boredom: MoodMax}, 5 * MoodStepMs, false,
+			Mood{Happiness: 50, Energy: MoodMax, Boredom: MoodMax}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.start
+			got.Advance(tt.ms, tt.moving)
+			if got != tt.want {
+				t.Errorf("Advance(%d, %v) = %+v, want %+v", tt.ms, tt.moving, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMood_AdvanceInSmallSteps(t *testing.T) {
+	whole, split := NewMood(), NewMood()
+	whole.Advance(30*MoodStepMs, true)
+	for i := 0; i < 300; i++ {
+		split.Advance(MoodStepMs/10, true)
+	}
+	if whole != split {
+		t.Errorf("30 s at once = %+v, in 100 ms ticks = %+v", whole, split)
+	}
+}
+
+func TestMood_Feeling(t *testing.T) {
+	tests := []struct {
+		name string
+		mood Mood
+		want int
+	}{
+		{"fresh pet is content", NewMood(), FeelingContent},
+		{"happy", Mood{Happiness: HappyHappiness, Energy: 100}, FeelingHappy},
+		{"bored", Mood{Happiness: 50, Energy: 100, Boredom: BoredBoredom}, FeelingBored},
+		{"tired", Mood{Happiness: 50, Energy: TiredEnergy}, FeelingTired},
+		{"afraid", Mood{Happiness: 50, Fear: AfraidFear, Energy: 100}, FeelingAfraid},
+		{"fear beats tiredness", Mood{Fear: MoodMax, Energy: 0}, FeelingAfraid},
+		{"tiredness beats boredom", Mood{Energy: 0, Boredom: MoodMax}, FeelingTired},
+		{"boredom beats happiness", Mood{Happiness: MoodMax, Energy: 100, Boredom: MoodMax}, FeelingBored},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mood.Feeling(); got != tt.want {
+				t.Errorf("%+v.Feeling() = %d, want %d", tt.mood, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMood_Scenarios(t *testing.T) {
+	t.Run("three edge scares in a row frighten the pet, then it calms down", func(t *testing.T) {
+		m := NewMood()
+		for i := 0; i < 3; i++ {
+			m.React(MoodEventEdgeScare)
+			m.Advance(2*MoodStepMs, true)
+		}
+		if got := m.Feeling(); got != FeelingAfraid {
+			t.Fatalf("feeling = %d after three edge scares, want FeelingAfraid", got)
+		}
+		m.Advance(20*MoodStepMs, true)
+		if got := m.Feeling(); got == FeelingAfraid {
+			t.Errorf("still afraid 20 s later (%+v)", m)
+		}
+	})
+	t.Run("a long wander tires the pet", func(t *testing.T) {
+		m := NewMood()
+		m.Advance((MoodMax-TiredEnergy)*EnergyDrainSteps*MoodStepMs, true)
+		m.Boredom = 0
+		if got := m.Feeling(); got != FeelingTired {
+			t.Errorf("feeling = %d (%+v), want FeelingTired", got, m)
+		}
+	})
+	t.Run("sitting idle gets boring until someone plays", func(t *testing.T) {
+		m := NewMood()
+		m.Advance(BoredBoredom/BoredomResting*MoodStepMs, false)
+		if got := m.Feeling(); got != FeelingBored {
+			t.Fatalf("feeling = %d (%+v), want FeelingBored", got, m)
+		}
+		m.React(MoodEventInteraction)
+		if got := m.Feeling(); got != FeelingHappy {
+			t.Errorf("feeling = %d (%+v) after playing, want FeelingHappy", got, m)
+		}
+	})
+}
+
+func TestMood_Rest(t *testing.T) {
+	m := Mood{Happiness: 30, Fear: 80, Energy: 5, Boredom: 90}
+	m.Rest()
+	if want := (Mood{Happiness: 30, Energy: MoodMax}); m != want {
+		t.Errorf("after Rest: %+v, want %+v", m, want)
+	}
+}
+
+func TestScaleCruiseSpeed(t *testing.T) {
+	tests := []struct {
+		name           string
+		speed, percent int
+		want           int
+	}{
+		{"full", MaxSpeed, 100, MaxSpeed},
+		{"afraid", MaxSpeed, AfraidSpeedPct, CreepSpeed + (MaxSpeed-CreepSpeed)*AfraidSpeedPct/100},
+		{"creep stays creep", CreepSpeed, 0, CreepSpeed},
+		{"stopped stays stopped", 0, 50, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScaleCruiseSpeed(tt.speed, tt.percent); got != tt.want {
+				t.Errorf("ScaleCruiseSpeed(%d, %d) = %d, want %d", tt.speed, tt.percent, got, tt.want)
+			}
+		})
+	}
+}
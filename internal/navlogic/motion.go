@@ -0,0 +1,60 @@
+package navlogic
+
+// MaxMotionSteps bounds a MotionPlan so it fits in a fixed array.
+const MaxMotionSteps = 4
+
+// MotionStep drives with a linear speed and angular rate (see MixDrive) for DurationMs.
+type MotionStep struct {
+	Linear     int
+	Angular    int
+	DurationMs uint32
+}
+
+// MotionPlan sequences timed MotionSteps; the main loop advances it by the elapsed time each tick.
+type MotionPlan struct {
+	steps     [MaxMotionSteps]MotionStep
+	count     int
+	index     int
+	elapsedMs uint32
+	started   bool
+}
+
+// Start replaces the plan with steps; steps past MaxMotionSteps are dropped.
+// Time counts from the first Advance, so a plan started mid-tick still gets its full duration.
+func (p *MotionPlan) Start(steps ...MotionStep) {
+	p.count = copy(p.steps[:], steps)
+	p.index = 0
+	p.elapsedMs = 0
+	p.started = false
+}
+
+func (p *MotionPlan) Cancel() {
+	p.count = 0
+	p.index = 0
+}
+
+func (p *MotionPlan) Active() bool {
+	return p.index < p.count
+}
+
+// Current returns the running step, or false once the plan has finished.
+func (p *MotionPlan) Current() (MotionStep, bool) {
+	if !p.Active() {
+		return MotionStep{}, false
+	}
+	return p.steps[p.index], true
+}
+
+// Advance moves the plan on by elapsedMs and returns the step now running, or false once it has finished.
+func (p *MotionPlan) Advance(elapsedMs uint32) (MotionStep, bool) {
+	if !p.started {
+		p.started = true
+	} else {
+		p.elapsedMs += elapsedMs
+	}
+	for p.Active() && p.elapsedMs >= p.steps[p.index].DurationMs {
+		p.elapsedMs -= p.steps[p.index].DurationMs
+		p.index++
+	}
+	return p.Current()
+}
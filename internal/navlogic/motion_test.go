@@ -0,0 +1,65 @@
+package navlogic
+
+import "testing"
+
+func TestMotionPlan_Advance(t *testing.T) {
+	reverse := MotionStep{Linear: -80, Angular: 40, DurationMs: 300}
+	turn := MotionStep{Linear: 20, Angular: 80, DurationMs: 200}
+	tests := []struct {
+		name     string
+		steps    []MotionStep
+		ticks    []uint32
+		want     MotionStep
+		wantLive bool
+	}{
+		{"first advance starts the clock", []MotionStep{reverse, turn}, []uint32{5000}, reverse, true},
+		{"still in first step", []MotionStep{reverse, turn}, []uint32{100, 100, 100}, reverse, true},
+		{"moves to second step", []MotionStep{reverse, turn}, []uint32{100, 100, 100, 100}, turn, true},
+		{"long tick skips a step", []MotionStep{reverse, turn}, []uint32{100, 400}, turn, true},
+		{"finishes", []MotionStep{reverse, turn}, []uint32{100, 300, 200}, MotionStep{}, false},
+		{"empty plan", nil, []uint32{100}, MotionStep{}, false},
+		{"zero duration step is skipped", []MotionStep{{Linear: 50}, turn}, []uint32{100}, turn, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p MotionPlan
+			p.Start(tt.steps...)
+			var got MotionStep
+			var live bool
+			for _, ms := range tt.ticks {
+				got, live = p.Advance(ms)
+			}
+			if got != tt.want || live != tt.wantLive {
+				t.Errorf("Advance = (%+v, %v), want (%+v, %v)", got, live, tt.want, tt.wantLive)
+			}
+			if p.Active() != tt.wantLive {
+				t.Errorf("Active = %v, want %v", p.Active(), tt.wantLive)
+			}
+		})
+	}
+}
+
+func TestMotionPlan_StartTruncatesAndCancel(t *testing.T) {
+	var p MotionPlan
+	steps := make([]MotionStep, MaxMotionSteps+2)
+	for i := range steps {
+		steps[i] = MotionStep{Linear: i, DurationMs: 10}
+	}
+	p.Start(steps...)
+	p.Advance(0)
+	for i := 0; i < MaxMotionSteps-1; i++ {
+		p.Advance(10)
+	}
+	if got, ok := p.Current(); !ok || got.Linear != MaxMotionSteps-1 {
+		t.Fatalf("Current = (%+v, %v), want last kept step", got, ok)
+	}
+	if _, ok := p.Advance(10); ok {
+		t.Error("plan ran past MaxMotionSteps")
+	}
+
+	p.Start(steps[0])
+	p.Cancel()
+	if p.Active() {
+		t.Error("Active after Cancel")
+	}
+}
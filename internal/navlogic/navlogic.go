@@ -7,6 +7,8 @@ const (
 	StateObstacleAvoidance
 	StateEdgeAvoidance
 	StateInteracting
+	StateGuarding
+	StateAlert
 )
 
 // NextStateFromSensors returns the next state from sensor inputs; edge takes precedence over obstacle.
@@ -43,7 +43,7 @@ func TestNextStateFromSensors_Moving(t *testing.T) {
 }
 
 func TestNextStateFromSensors_OtherStatesUnchanged(t *testing.T) {
-	otherStates := []int{StateObstacleAvoidance, StateEdgeAvoidance, StateInteracting}
+	otherStates := []int{StateObstacleAvoidance, StateEdgeAvoidance, StateInteracting, StateGuarding, StateAlert}
 	for _, s := range otherStates {
 		got := NextStateFromSensors(s, true, true)
 		if got != s {
@@ -0,0 +1,44 @@
+package navlogic_test
+
+// Regression traces: every testdata/*.trace is replayed through the pet's navigation (pet.Replay), which
+// makes its decisions with this package, and must meet its expect lines. To turn a misbehaviour seen on a
+// board into a test, capture telemetry, convert it with
+// "go run ./cmd/tinypet-replay -telemetry -save testdata/<name>.trace <capture>", trim it and add the
+// expect lines for the right behaviour.
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/pet"
+)
+
+func TestReplayTraces(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.trace"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no traces in testdata")
+	}
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			trace, err := pet.ReadTrace(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(trace.Expects) == 0 {
+				t.Fatal("trace has no expect lines")
+			}
+			if err := trace.Check(pet.Replay(trace)); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package navlogic
+
+const (
+	SleepAfterMs     = 300000
+	SleepCheckMs     = 250
+	WakePollMs       = 20 // the wake button is polled this often while asleep, well within a short tap
+	SleepWakeCm      = 20
+	SleepScreenOnMs  = 5000
+	SleepScreenOffMs = 25000
+	ZzzFrameMs       = 600
+	ZzzFrames        = 3
+)
+
+// SleepTimer counts wandering time until the pet gets sleepy; AfterMs 0 keeps it awake.
+type SleepTimer struct {
+	AfterMs  uint32
+	wanderMs uint32
+}
+
+// Advance adds elapsedMs of wandering (time spent otherwise does not count) and reports whether the pet is sleepy.
+func (s *SleepTimer) Advance(elapsedMs uint32, wandering bool) bool {
+	if s.AfterMs == 0 {
+		return false
+	}
+	if wandering && s.wanderMs < s.AfterMs {
+		s.wanderMs += elapsedMs
+	}
+	return s.wanderMs >= s.AfterMs
+}
+
+func (s *SleepTimer) Reset() {
+	s.wanderMs = 0
+}
+
+// SleepCycle is the time spent asleep; it drives the Zzz animation and turns the screen off
+// for SleepScreenOffMs after every SleepScreenOnMs.
+type SleepCycle struct {
+	elapsedMs uint32
+}
+
+func (c *SleepCycle) Advance(elapsedMs uint32) {
+	c.elapsedMs = (c.elapsedMs + elapsedMs) % (SleepScreenOnMs + SleepScreenOffMs)
+}
+
+func (c *SleepCycle) Reset() {
+	c.elapsedMs = 0
+}
+
+func (c SleepCycle) ScreenOn() bool {
+	return c.elapsedMs < SleepScreenOnMs
+}
+
+// ZzzCount is how many Zs the sleeping face shows, growing from 1 to ZzzFrames and starting over.
+func (c SleepCycle) ZzzCount() int {
+	return int(c.elapsedMs/ZzzFrameMs)%ZzzFrames + 1
+}
+
+// ShouldWake reports whether a sleeping pet wakes: something within SleepWakeCm of the ultrasonic sensor or a button press.
+func ShouldWake(distance int, buttonPressed bool) bool {
+	return buttonPressed || IsWithinThreshold(distance, SleepWakeCm)
+}
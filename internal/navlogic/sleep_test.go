@@ -0,0 +1,93 @@
+package navlogic
+
+import "testing"
+
+func TestSleepTimer(t *testing.T) {
+	tests := []struct {
+		name      string
+		afterMs   uint32
+		wandering []bool
+		want      bool
+	}{
+		{"not yet", 1000, []bool{true, true, true}, false},
+		{"sleepy after wandering", 1000, []bool{true, true, true, true, true}, true},
+		{"only wandering counts", 1000, []bool{true, false, true, false, true, false}, false},
+		{"stays sleepy", 1000, []bool{true, true, true, true, false, false}, true},
+		{"zero never sleeps", 0, []bool{true, true, true, true, true, true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := SleepTimer{AfterMs: tt.afterMs}
+			var got bool
+			for _, w := range tt.wandering {
+				got = s.Advance(250, w)
+			}
+			if got != tt.want {
+				t.Errorf("sleepy = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSleepTimer_Reset(t *testing.T) {
+	s := SleepTimer{AfterMs: 500}
+	s.Advance(500, true)
+	s.Reset()
+	if s.Advance(100, true) {
+		t.Error("sleepy straight after Reset")
+	}
+}
+
+func TestSleepCycle(t *testing.T) {
+	tests := []struct {
+		name       string
+		elapsedMs  uint32
+		wantScreen bool
+		wantZzz    int
+	}{
+		{"just asleep", 0, true, 1},
+		{"second frame", ZzzFrameMs, true, 2},
+		{"third frame", 2 * ZzzFrameMs, true, 3},
+		{"frames wrap", 3 * ZzzFrameMs, true, 1},
+		{"screen goes off", SleepScreenOnMs, false, 3},
+		{"screen stays off", SleepScreenOnMs + SleepScreenOffMs - 1, false, 2},
+		{"screen comes back", SleepScreenOnMs + SleepScreenOffMs, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c SleepCycle
+			for ms := tt.elapsedMs; ms > 0; {
+				step := min(ms, SleepCheckMs)
+				c.Advance(step)
+				ms -= step
+			}
+			if got := c.ScreenOn(); got != tt.wantScreen {
+				t.Errorf("ScreenOn() = %v, want %v", got, tt.wantScreen)
+			}
+			if got := c.ZzzCount(); got != tt.wantZzz {
+				t.Errorf("ZzzCount() = %d, want %d", got, tt.wantZzz)
+			}
+		})
+	}
+}
+
+func TestShouldWake(t *testing.T) {
+	tests := []struct {
+		name     string
+		distance int
+		button   bool
+		want     bool
+	}{
+		{"hand close", SleepWakeCm - 1, false, true},
+		{"at wake range", SleepWakeCm, false, false},
+		{"nothing ahead", TimeoutDistance, false, false},
+		{"button", TimeoutDistance, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShouldWake(tt.distance, tt.button); got != tt.want {
+				t.Errorf("ShouldWake(%d, %v) = %v, want %v", tt.distance, tt.button, got, tt.want)
+			}
+		})
+	}
+}
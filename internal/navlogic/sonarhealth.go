@@ -0,0 +1,40 @@
+package navlogic
+
+const (
+	SonarFaultLimit      = 5
+	SonarRecoverReadings = 10
+)
+
+// SonarHealth watches ultrasonic readings for a broken sensor. SonarFaultLimit faults in a row mark the
+// sensor degraded; it is trusted again after SonarRecoverReadings fault-free readings in a row.
+type SonarHealth struct {
+	faults   uint8
+	good     uint8
+	degraded bool
+}
+
+// Update records a reading and reports whether the sensor is degraded.
+func (h *SonarHealth) Update(r RangeReading) bool {
+	if r.IsFault() {
+		h.good = 0
+		if h.faults < SonarFaultLimit {
+			h.faults++
+		}
+		if h.faults >= SonarFaultLimit {
+			h.degraded = true
+		}
+		return h.degraded
+	}
+	h.faults = 0
+	if h.degraded {
+		h.good++
+		if h.good >= SonarRecoverReadings {
+			h.degraded, h.good = false, 0
+		}
+	}
+	return h.degraded
+}
+
+func (h *SonarHealth) Degraded() bool {
+	return h.degraded
+}
@@ -0,0 +1,67 @@
+package navlogic
+
+import "testing"
+
+func TestSonarHealth(t *testing.T) {
+	ok := RangeReading{Cm: 50}
+	far := RangeFromCm(TimeoutDistance)
+	noEcho := RangeReading{Cm: TimeoutDistance, Status: RangeNoEcho}
+	stuck := RangeReading{Cm: TimeoutDistance, Status: RangeStuckHigh}
+	repeat := func(r RangeReading, n int) []RangeReading {
+		rs := make([]RangeReading, n)
+		for i := range rs {
+			rs[i] = r
+		}
+		return rs
+	}
+	tests := []struct {
+		name     string
+		readings []RangeReading
+		want     bool
+	}{
+		{"healthy", repeat(ok, 20), false},
+		{"nothing in range is not a fault", repeat(far, 20), false},
+		{"a few faults are tolerated", repeat(noEcho, SonarFaultLimit-1), false},
+		{"repeated no echo degrades", repeat(noEcho, SonarFaultLimit), true},
+		{"repeated stuck echo degrades", repeat(stuck, SonarFaultLimit), true},
+		{"faults must be consecutive", append(append(repeat(noEcho, SonarFaultLimit-1), ok), repeat(stuck, SonarFaultLimit-1)...), false},
+		{"stays degraded until enough good readings", append(repeat(noEcho, SonarFaultLimit), repeat(ok, SonarRecoverReadings-1)...), true},
+		{"recovers", append(repeat(noEcho, SonarFaultLimit), repeat(far, SonarRecoverReadings)...), false},
+		{"a fault restarts recovery", append(append(append(repeat(noEcho, SonarFaultLimit), repeat(ok, SonarRecoverReadings-1)...), noEcho), repeat(ok, SonarRecoverReadings-1)...), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var h SonarHealth
+			for _, r := range tt.readings {
+				h.Update(r)
+			}
+			if got := h.Degraded(); got != tt.want {
+				t.Errorf("Degraded = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRangeReading(t *testing.T) {
+	tests := []struct {
+		name      string
+		reading   RangeReading
+		wantCm    int
+		wantFault bool
+	}{
+		{"measured", RangeReading{Cm: 42}, 42, false},
+		{"out of range", RangeFromCm(TimeoutDistance), TimeoutDistance, false},
+		{"stuck high", RangeReading{Cm: 12, Status: RangeStuckHigh}, TimeoutDistance, true},
+		{"no echo", RangeReading{Status: RangeNoEcho}, TimeoutDistance, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.reading.Distance(); got != tt.wantCm {
+				t.Errorf("Distance = %d, want %d", got, tt.wantCm)
+			}
+			if got := tt.reading.IsFault(); got != tt.wantFault {
+				t.Errorf("IsFault = %v, want %v", got, tt.wantFault)
+			}
+		})
+	}
+}
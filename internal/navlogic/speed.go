@@ -0,0 +1,47 @@
+package navlogic
+
+const (
+	MaxSpeed       = 100
+	CreepSpeed     = 35
+	SlowdownFactor = 3
+)
+
+// CruiseSpeed returns the forward speed (CreepSpeed..MaxSpeed) for the distance ahead.
+// Speed falls linearly from MaxSpeed at SlowdownFactor×threshold to CreepSpeed at threshold;
+// near an edge the pet always creeps. A timeout means nothing ahead.
+func CruiseSpeed(distance, threshold int, nearEdge bool) int {
+	if nearEdge {
+		return CreepSpeed
+	}
+	slowdown := threshold * SlowdownFactor
+	if distance == TimeoutDistance || distance >= slowdown {
+		return MaxSpeed
+	}
+	if distance <= threshold {
+		return CreepSpeed
+	}
+	return CreepSpeed + (MaxSpeed-CreepSpeed)*(distance-threshold)/(slowdown-threshold)
+}
+
+// RampToward moves current toward target by at most step.
+func RampToward(current, target, step int) int {
+	switch {
+	case target > current+step:
+		return current + step
+	case target < current-step:
+		return current - step
+	default:
+		return target
+	}
+}
+
+// ClampSpeed limits speed to -MaxSpeed..MaxSpeed.
+func ClampSpeed(speed int) int {
+	if speed > MaxSpeed {
+		return MaxSpeed
+	}
+	if speed < -MaxSpeed {
+		return -MaxSpeed
+	}
+	return speed
+}
@@ -0,0 +1,62 @@
+package navlogic
+
+import "testing"
+
+func TestCruiseSpeed(t *testing.T) {
+	const threshold = 20
+	tests := []struct {
+		name     string
+		distance int
+		nearEdge bool
+		want     int
+	}{
+		{"nothing ahead", TimeoutDistance, false, MaxSpeed},
+		{"far away", 200, false, MaxSpeed},
+		{"at slowdown distance", threshold * SlowdownFactor, false, MaxSpeed},
+		{"halfway through slowdown", 40, false, (CreepSpeed + MaxSpeed) / 2},
+		{"just outside threshold", 21, false, CreepSpeed + (MaxSpeed-CreepSpeed)/(2*threshold)},
+		{"at threshold", threshold, false, CreepSpeed},
+		{"inside threshold", 5, false, CreepSpeed},
+		{"near edge with nothing ahead", TimeoutDistance, true, CreepSpeed},
+		{"near edge far from obstacle", 200, true, CreepSpeed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CruiseSpeed(tt.distance, threshold, tt.nearEdge)
+			if got != tt.want {
+				t.Errorf("CruiseSpeed(%d, %d, %v) = %d, want %d", tt.distance, threshold, tt.nearEdge, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRampToward(t *testing.T) {
+	tests := []struct {
+		current, target, step, want int
+	}{
+		{0, 100, 20, 20},
+		{90, 100, 20, 100},
+		{100, 100, 20, 100},
+		{100, 0, 20, 80},
+		{10, -100, 20, -10},
+		{-100, 100, 20, -80},
+		{-15, 0, 20, 0},
+	}
+	for _, tt := range tests {
+		got := RampToward(tt.current, tt.target, tt.step)
+		if got != tt.want {
+			t.Errorf("RampToward(%d, %d, %d) = %d, want %d", tt.current, tt.target, tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestClampSpeed(t *testing.T) {
+	tests := []struct{ in, want int }{
+		{0, 0}, {55, 55}, {-55, -55}, {100, 100}, {101, 100}, {-250, -100},
+	}
+	for _, tt := range tests {
+		if got := ClampSpeed(tt.in); got != tt.want {
+			t.Errorf("ClampSpeed(%d) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
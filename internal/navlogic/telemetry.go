@@ -0,0 +1,138 @@
+package navlogic
+
+import "errors"
+
+// Telemetry frame layout (little endian), TelemetryFrameSize bytes:
+//
+//	0  0xA5 0x5A        sync
+//	2  version          TelemetryVersion
+//	3  sequence         uint16, wraps
+//	5  time             uint32, ms
+//	9  state            uint8, StateIdle etc.
+//	10 behavior mode    uint8
+//	11 flags            TelemetryFlag*
+//	12 distance         int16, cm (-1 = nothing in range or a fault, see TelemetryFlagSonarFault)
+//	14 IR raw           TelemetryIRSensors × uint16
+//	22 motor command    int8 left, int8 right (-100..100)
+//	24 loop period      uint16, ms since the previous tick
+//	26 busy time        uint16, ms the tick took
+//	28 IR fitted        bit i set when IR sensor i is fitted
+//	29 CRC16 of bytes 0..28
+const (
+	TelemetryVersion   = 2
+	TelemetryIRSensors = 4
+	TelemetryFrameSize = 31
+	telemetrySync0     = 0xA5
+	telemetrySync1     = 0x5A
+)
+
+// Telemetry flags.
+const (
+	TelemetryFlagManual = 1 << iota
+	TelemetryFlagAsleep
+	TelemetryFlagSonarDegraded
+	TelemetryFlagBatteryLow
+	TelemetryFlagBatteryFlat
+	TelemetryFlagSonarFault // the last ultrasonic reading was a fault, not nothing in range
+)
+
+var (
+	ErrTelemetrySync     = errors.New("telemetry frame sync missing")
+	ErrTelemetryVersion  = errors.New("telemetry frame version mismatch")
+	ErrTelemetryChecksum = errors.New("telemetry frame checksum mismatch")
+)
+
+// TelemetrySample is what the pet reports about one main-loop tick.
+type TelemetrySample struct {
+	Seq        uint16
+	TimeMs     uint32
+	State      uint8
+	Mode       uint8
+	Flags      uint8
+	Distance   int16
+	IR         [TelemetryIRSensors]uint16
+	LeftMotor  int8
+	RightMotor int8
+	LoopMs     uint16
+	BusyMs     uint16
+	IRFitted   uint8 // bit i set when IR[i] comes from a fitted sensor
+}
+
+// Encode writes s as a frame into buf.
+func (s TelemetrySample) Encode(buf *[TelemetryFrameSize]byte) {
+	buf[0], buf[1], buf[2] = telemetrySync0, telemetrySync1, TelemetryVersion
+	putUint16(buf[3:], s.Seq)
+	putUint16(buf[5:], uint16(s.TimeMs))
+	putUint16(buf[7:], uint16(s.TimeMs>>16))
+	buf[9], buf[10], buf[11] = s.State, s.Mode, s.Flags
+	putUint16(buf[12:], uint16(s.Distance))
+	for i, v := range s.IR {
+		putUint16(buf[14+2*i:], v)
+	}
+	buf[22], buf[23] = byte(s.LeftMotor), byte(s.RightMotor)
+	putUint16(buf[24:], s.LoopMs)
+	putUint16(buf[26:], s.BusyMs)
+	buf[28] = s.IRFitted
+	putUint16(buf[29:], CRC16(buf[:29]))
+}
+
+// DecodeTelemetryFrame checks and decodes one frame.
+func DecodeTelemetryFrame(buf *[TelemetryFrameSize]byte) (TelemetrySample, error) {
+	var s TelemetrySample
+	if buf[0] != telemetrySync0 || buf[1] != telemetrySync1 {
+		return s, ErrTelemetrySync
+	}
+	if buf[2] != TelemetryVersion {
+		return s, ErrTelemetryVersion
+	}
+	if getUint16(buf[29:]) != CRC16(buf[:29]) {
+		return s, ErrTelemetryChecksum
+	}
+	s.Seq = getUint16(buf[3:])
+	s.TimeMs = uint32(getUint16(buf[5:])) | uint32(getUint16(buf[7:]))<<16
+	s.State, s.Mode, s.Flags = buf[9], buf[10], buf[11]
+	s.Distance = int16(getUint16(buf[12:]))
+	for i := range s.IR {
+		s.IR[i] = getUint16(buf[14+2*i:])
+	}
+	s.LeftMotor, s.RightMotor = int8(buf[22]), int8(buf[23])
+	s.LoopMs = getUint16(buf[24:])
+	s.BusyMs = getUint16(buf[26:])
+	s.IRFitted = buf[28]
+	return s, nil
+}
+
+// NextTelemetryFrame finds the first valid frame in data, stepping over console text and corrupt frames.
+// used is how many bytes of data were consumed and skipped how many of those were not part of the frame;
+// ok is false when no complete frame is left, in which case data[used:] should be kept for the next call.
+func NextTelemetryFrame(data []byte) (s TelemetrySample, used, skipped int, ok bool) {
+	var frame [TelemetryFrameSize]byte
+	for start := 0; start+TelemetryFrameSize <= len(data); start++ {
+		if data[start] != telemetrySync0 || data[start+1] != telemetrySync1 {
+			continue
+		}
+		copy(frame[:], data[start:])
+		if sample, err := DecodeTelemetryFrame(&frame); err == nil {
+			return sample, start + TelemetryFrameSize, start, true
+		}
+	}
+	// Keep the tail, which may be the start of a frame still arriving.
+	used = len(data) - (TelemetryFrameSize - 1)
+	if used < 0 {
+		used = 0
+	}
+	return s, used, used, false
+}
+
+// SeqGap returns how many frames were lost between sequence numbers prev and next.
+func SeqGap(prev, next uint16) int {
+	return int(next-prev) - 1
+}
+
+func putUint16(b []byte, v uint16) {
+	b[0], b[1] = byte(v), byte(v>>8)
+}
+
+func getUint16(b []byte) uint16 {
+	return uint16(b[0]) | uint16(b[1])<<8
+}
@@ -0,0 +1,149 @@
+package navlogic
+
+import "testing"
+
+var testSample = TelemetrySample{
+	Seq:        0xFFFE,
+	TimeMs:     0x01020304,
+	State:      StateObstacleAvoidance,
+	Mode:       2,
+	Flags:      TelemetryFlagManual | TelemetryFlagBatteryLow | TelemetryFlagSonarFault,
+	Distance:   -1,
+	IR:         [TelemetryIRSensors]uint16{20000, 0, 512, 0xFFFF},
+	LeftMotor:  -100,
+	RightMotor: 45,
+	LoopMs:     10,
+	BusyMs:     3,
+	IRFitted:   0b0111,
+}
+
+func encodeSample(s TelemetrySample) []byte {
+	var buf [TelemetryFrameSize]byte
+	s.Encode(&buf)
+	return buf[:]
+}
+
+func TestTelemetrySample_RoundTrip(t *testing.T) {
+	var buf [TelemetryFrameSize]byte
+	testSample.Encode(&buf)
+	if buf[0] != 0xA5 || buf[1] != 0x5A || buf[2] != TelemetryVersion {
+		t.Errorf("header = % x, want a5 5a %02x", buf[:3], TelemetryVersion)
+	}
+	got, err := DecodeTelemetryFrame(&buf)
+	if err != nil || got != testSample {
+		t.Errorf("round trip = (%+v, %v), want (%+v, nil)", got, err, testSample)
+	}
+}
+
+func TestDecodeTelemetryFrame_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(buf *[TelemetryFrameSize]byte)
+		want    error
+	}{
+		{"bad sync", func(buf *[TelemetryFrameSize]byte) { buf[1] = 'x' }, ErrTelemetrySync},
+		{"newer version", func(buf *[TelemetryFrameSize]byte) { buf[2]++ }, ErrTelemetryVersion},
+		{"flipped bit", func(buf *[TelemetryFrameSize]byte) { buf[14] ^= 0x10 }, ErrTelemetryChecksum},
+		{"flipped fitted bit", func(buf *[TelemetryFrameSize]byte) { buf[28] ^= 0x08 }, ErrTelemetryChecksum},
+		{"bad CRC", func(buf *[TelemetryFrameSize]byte) { buf[TelemetryFrameSize-1]++ }, ErrTelemetryChecksum},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf [TelemetryFrameSize]byte
+			testSample.Encode(&buf)
+			tt.corrupt(&buf)
+			if _, err := DecodeTelemetryFrame(&buf); err != tt.want {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextTelemetryFrame(t *testing.T) {
+	frame := encodeSample(testSample)
+	corrupt := encodeSample(testSample)
+	corrupt[20] ^= 1
+	join := func(parts ...[]byte) []byte {
+		var out []byte
+		for _, p := range parts {
+			out = append(out, p...)
+		}
+		return out
+	}
+	tests := []struct {
+		name        string
+		data        []byte
+		wantOK      bool
+		wantUsed    int
+		wantSkipped int
+	}{
+		{"empty", nil, false, 0, 0},
+		{"one frame", frame, true, TelemetryFrameSize, 0},
+		{"console reply first", join([]byte("ok\r\n"), frame), true, 4 + TelemetryFrameSize, 4},
+		{"corrupt frame first", join(corrupt, frame), true, 2 * TelemetryFrameSize, TelemetryFrameSize},
+		{"partial frame", frame[:TelemetryFrameSize-1], false, 0, 0},
+		{"text then partial frame", join([]byte("hello\r\n"), frame[:10]), false, 0, 0},
+		{"text only", make([]byte, 100), false, 100 - (TelemetryFrameSize - 1), 100 - (TelemetryFrameSize - 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, used, skipped, ok := NextTelemetryFrame(tt.data)
+			if ok != tt.wantOK || used != tt.wantUsed {
+				t.Fatalf("ok, used = %v, %d; want %v, %d", ok, used, tt.wantOK, tt.wantUsed)
+			}
+			if skipped != tt.wantSkipped || ok && got != testSample {
+				t.Errorf("skipped, sample = %d, %+v; want %d, %+v", skipped, got, tt.wantSkipped, testSample)
+			}
+		})
+	}
+}
+
+func TestNextTelemetryFrame_Stream(t *testing.T) {
+	// A capture split at arbitrary points decodes to every frame once.
+	var stream []byte
+	for seq := uint16(0); seq < 5; seq++ {
+		s := testSample
+		s.Seq = seq
+		stream = append(stream, encodeSample(s)...)
+		stream = append(stream, "ok\r\n"...)
+	}
+	var pending []byte
+	var seqs []uint16
+	skipped := 0
+	for i := 0; i < len(stream); i += 7 {
+		end := min(i+7, len(stream))
+		pending = append(pending, stream[i:end]...)
+		for {
+			s, used, skip, ok := NextTelemetryFrame(pending)
+			pending = pending[used:]
+			skipped += skip
+			if !ok {
+				break
+			}
+			seqs = append(seqs, s.Seq)
+		}
+	}
+	if len(seqs) != 5 || seqs[4] != 4 {
+		t.Errorf("sequence numbers = %v, want 0..4", seqs)
+	}
+	if skipped != 4*4 {
+		t.Errorf("skipped = %d, want %d", skipped, 4*4)
+	}
+}
+
+func TestSeqGap(t *testing.T) {
+	tests := []struct {
+		prev, next uint16
+		want       int
+	}{
+		{1, 2, 0},
+		{1, 5, 3},
+		{0xFFFF, 0, 0},
+		{0xFFFE, 1, 2},
+	}
+	for _, tt := range tests {
+		if got := SeqGap(tt.prev, tt.next); got != tt.want {
+			t.Errorf("SeqGap(%d, %d) = %d, want %d", tt.prev, tt.next, got, tt.want)
+		}
+	}
+}
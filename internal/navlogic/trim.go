@@ -0,0 +1,54 @@
+package navlogic
+
+const (
+	TrimStep          = 4
+	MaxTrim           = 30
+	TrimAnswerLeftCm  = 10
+	TrimAnswerRightCm = 25
+)
+
+// Answers to "which way did it veer?" in the trim procedure, given with a hand in front of the ultrasonic sensor.
+const (
+	TrimStraight = iota
+	TrimVeeredLeft
+	TrimVeeredRight
+)
+
+// TrimAnswer reads a trim answer from a distance: a hand closer than TrimAnswerLeftCm means it veered left,
+// up to TrimAnswerRightCm means right, nothing nearer means it drove straight.
+func TrimAnswer(distance int) int {
+	switch {
+	case IsWithinThreshold(distance, TrimAnswerLeftCm):
+		return TrimVeeredLeft
+	case IsWithinThreshold(distance, TrimAnswerRightCm):
+		return TrimVeeredRight
+	default:
+		return TrimStraight
+	}
+}
+
+// AdjustTrim moves the per-motor trims (percent, -MaxTrim..0) one TrimStep against the veer.
+// Only the faster motor is slowed, since the slower one may already be at full speed: veering left means
+// the right motor is faster, so a slowed left motor is first given back speed, then the right one is slowed.
+func AdjustTrim(leftTrim, rightTrim, answer int) (int, int) {
+	switch answer {
+	case TrimVeeredLeft:
+		if leftTrim < 0 {
+			leftTrim = min(leftTrim+TrimStep, 0)
+		} else {
+			rightTrim = max(rightTrim-TrimStep, -MaxTrim)
+		}
+	case TrimVeeredRight:
+		if rightTrim < 0 {
+			rightTrim = min(rightTrim+TrimStep, 0)
+		} else {
+			leftTrim = max(leftTrim-TrimStep, -MaxTrim)
+		}
+	}
+	return leftTrim, rightTrim
+}
+
+// ApplyTrim scales a signed wheel speed by a trim in percent.
+func ApplyTrim(speed, trim int) int {
+	return speed * (100 + trim) / 100
+}
@@ -0,0 +1,63 @@
+package navlogic
+
+import "testing"
+
+func TestTrimAnswer(t *testing.T) {
+	tests := []struct {
+		distance int
+		want     int
+	}{
+		{5, TrimVeeredLeft},
+		{TrimAnswerLeftCm - 1, TrimVeeredLeft},
+		{TrimAnswerLeftCm, TrimVeeredRight},
+		{TrimAnswerRightCm - 1, TrimVeeredRight},
+		{TrimAnswerRightCm, TrimStraight},
+		{TimeoutDistance, TrimStraight},
+	}
+	for _, tt := range tests {
+		if got := TrimAnswer(tt.distance); got != tt.want {
+			t.Errorf("TrimAnswer(%d) = %d, want %d", tt.distance, got, tt.want)
+		}
+	}
+}
+
+func TestAdjustTrim(t *testing.T) {
+	tests := []struct {
+		name                string
+		left, right, answer int
+		wantL, wantR        int
+	}{
+		{"straight keeps trim", -4, 0, TrimStraight, -4, 0},
+		{"veered left slows right", 0, 0, TrimVeeredLeft, 0, -TrimStep},
+		{"veered right slows left", 0, 0, TrimVeeredRight, -TrimStep, 0},
+		{"veered left gives left back speed first", -8, 0, TrimVeeredLeft, -4, 0},
+		{"veered right gives right back speed first", 0, -2, TrimVeeredRight, 0, 0},
+		{"right trim stops at MaxTrim", 0, -MaxTrim + 1, TrimVeeredLeft, 0, -MaxTrim},
+		{"left trim stops at MaxTrim", -MaxTrim, 0, TrimVeeredRight, -MaxTrim, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, r := AdjustTrim(tt.left, tt.right, tt.answer)
+			if l != tt.wantL || r != tt.wantR {
+				t.Errorf("AdjustTrim(%d, %d, %d) = (%d, %d), want (%d, %d)", tt.left, tt.right, tt.answer, l, r, tt.wantL, tt.wantR)
+			}
+		})
+	}
+}
+
+func TestApplyTrim(t *testing.T) {
+	tests := []struct {
+		speed, trim, want int
+	}{
+		{100, 0, 100},
+		{100, -8, 92},
+		{-100, -8, -92},
+		{50, -10, 45},
+		{0, -30, 0},
+	}
+	for _, tt := range tests {
+		if got := ApplyTrim(tt.speed, tt.trim); got != tt.want {
+			t.Errorf("ApplyTrim(%d, %d) = %d, want %d", tt.speed, tt.trim, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package pet
+
+import (
+	"github.com/GyeongHoKim/tiny-pet/internal/logging"
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+const (
+	// BATTERY_CHECK_MS is how often the pack voltage is read.
+	BATTERY_CHECK_MS = 1000
+	// BATTERY_WARN_MS is how often the low-battery code repeats until the pack is charged.
+	BATTERY_WARN_MS = 60000
+)
+
+// BatteryLevel returns the level of the pack (navlogic.BatteryOK etc.); BatteryAbsent without a battery monitor.
+func (p *Pet) BatteryLevel() int {
+	return p.batteryLevel
+}
+
+func (p *Pet) BatteryPercent() int {
+	return p.battery.Percent()
+}
+
+// isBatteryFlat reports whether the pack is too low to drive the motors; they stay stopped until it recovers.
+func (p *Pet) isBatteryFlat() bool {
+	return p.batteryLevel == navlogic.BatteryCritical
+}
+
+// checkBattery reads the pack every BATTERY_CHECK_MS and repeats the low-battery warning every BATTERY_WARN_MS.
+func (p *Pet) checkBattery(elapsed uint32) {
+	if p.robot.Battery == nil {
+		return
+	}
+	p.batteryWarnMs += elapsed
+	p.batteryCheckMs += elapsed
+	if p.batteryCheckMs < BATTERY_CHECK_MS {
+		return
+	}
+	p.batteryCheckMs = 0
+
+	level := p.battery.Update(p.robot.Battery.Millivolts())
+	if level != p.batteryLevel {
+		p.setBatteryLevel(level)
+	} else if level == navlogic.BatteryLow && p.batteryWarnMs >= BATTERY_WARN_MS {
+		p.warnLowBattery()
+	}
+}
+
+// setBatteryLevel reacts to a new battery level: a warning and slower cruising when low, a forced stop
+// when flat, and a fresh start from idle once a flat pack has recovered.
+func (p *Pet) setBatteryLevel(level int) {
+	wasFlat := p.isBatteryFlat()
+	p.batteryLevel = level
+	switch {
+	case p.isBatteryFlat():
+		logging.Error(logging.Power, "Battery flat: motors stopped", p.battery.Percent())
+		p.navigation.EmergencyStop()
+		if p.asleep {
+			p.asleep = false
+			p.screenOn = true
+			p.display.SetScreen(true)
+		}
+		p.behaviors.IndicateBatteryFlat()
+		p.display.ShowExpression(EXPR_LOW_BATTERY)
+		return
+	case level == navlogic.BatteryLow && !wasFlat:
+		logging.Warn(logging.Power, "Battery low", p.battery.Percent())
+		p.warnLowBattery()
+	}
+	if wasFlat {
+		p.sleepTimer.Reset()
+		p.navElapsed = 0
+		p.lastState = -1
+	}
+	p.navigation.SetSpeedPercent(p.speedPercent())
+	if !p.asleep {
+		p.showFace(p.navigation.GetCurrentState())
+	}
+}
+
+func (p *Pet) warnLowBattery() {
+	p.batteryWarnMs = 0
+	p.behaviors.SoundErrorCode(ERROR_CODE_LOW_BATTERY)
+}
+
+// speedPercent is the cruise speed for the current feeling, capped at navlogic.BatteryLowSpeedPct on a low battery.
+func (p *Pet) speedPercent() int {
+	percent := navlogic.FeelingSpeedPercent(p.feeling)
+	if p.batteryLevel == navlogic.BatteryLow {
+		percent = min(percent, navlogic.BatteryLowSpeedPct)
+	}
+	return percent
+}
@@ -0,0 +1,155 @@
+package pet
+
+import (
+	"testing"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+const (
+	testLowBatteryMv  = 3600
+	testFlatBatteryMv = 3400
+)
+
+// drainTo sets the pack voltage and runs the pet until the smoothed reading reaches level.
+func drainTo(t *testing.T, p *Pet, fr *FakeRobot, mv uint16, level int) {
+	t.Helper()
+	fr.Battery.Mv = mv
+	for i := 0; i < 60 && p.BatteryLevel() != level; i++ {
+		runFor(p, fr, BATTERY_CHECK_MS)
+	}
+	if got := p.BatteryLevel(); got != level {
+		t.Fatalf("battery level = %d at %d mV, want %d", got, mv, level)
+	}
+}
+
+func TestBattery_FullPackRunsNormally(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	runFor(p, fr, 1000)
+
+	if got := p.BatteryLevel(); got != navlogic.BatteryOK {
+		t.Errorf("battery level = %d, want BatteryOK", got)
+	}
+	if got := p.BatteryPercent(); got != 100 {
+		t.Errorf("battery = %d%%, want 100%%", got)
+	}
+	if fr.LeftMotor.Speed != MAX_SPEED {
+		t.Errorf("speed = %d, want %d", fr.LeftMotor.Speed, MAX_SPEED)
+	}
+}
+
+func TestBattery_NoMonitorOrUSBPowerIsIgnored(t *testing.T) {
+	tests := []struct {
+		name      string
+		fitted    bool
+		batteryMv uint16
+	}{
+		{"not fitted", false, 0},
+		{"USB power", true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fr := NewFakeRobot()
+			fr.Battery.Mv = tt.batteryMv
+			if !tt.fitted {
+				fr.Robot.Battery = nil
+			}
+			p := New(fr.Robot)
+			runFor(p, fr, 3*BATTERY_CHECK_MS)
+
+			if got := p.BatteryLevel(); got != navlogic.BatteryAbsent {
+				t.Errorf("battery level = %d, want BatteryAbsent", got)
+			}
+			if fr.LeftMotor.Speed != MAX_SPEED {
+				t.Errorf("speed = %d, want %d", fr.LeftMotor.Speed, MAX_SPEED)
+			}
+		})
+	}
+}
+
+func TestBattery_LowWarnsAndSlows(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	runFor(p, fr, 1000)
+	beeps := fr.Buzzer.Pulses
+
+	drainTo(t, p, fr, testLowBatteryMv, navlogic.BatteryLow)
+	runFor(p, fr, 2000)
+	if got := fr.Buzzer.Pulses - beeps; got != ERROR_CODE_LOW_BATTERY {
+		t.Errorf("beeps = %d, want the %d-beep low-battery code", got, ERROR_CODE_LOW_BATTERY)
+	}
+	if got := p.display.GetCurrentExpression(); got != EXPR_LOW_BATTERY {
+		t.Errorf("expression = %d, want EXPR_LOW_BATTERY", got)
+	}
+	if want := navlogic.ScaleCruiseSpeed(MAX_SPEED, navlogic.BatteryLowSpeedPct); fr.LeftMotor.Speed > want {
+		t.Errorf("speed = %d, want at most %d on a low battery", fr.LeftMotor.Speed, want)
+	}
+
+	// Rested, so a bored sigh does not join in.
+	p.mood.Rest()
+	beeps = fr.Buzzer.Pulses
+	runFor(p, fr, BATTERY_WARN_MS)
+	if got := fr.Buzzer.Pulses - beeps; got != ERROR_CODE_LOW_BATTERY {
+		t.Errorf("beeps in the next BATTERY_WARN_MS = %d, want the code repeated once", got)
+	}
+}
+
+func TestBattery_FlatStopsMotorsUntilCharged(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	runFor(p, fr, 1000)
+
+	drainTo(t, p, fr, testFlatBatteryMv, navlogic.BatteryCritical)
+	runFor(p, fr, 5000)
+	if fr.LeftMotor.Speed != 0 || fr.RightMotor.Speed != 0 {
+		t.Errorf("motors = (%d, %d), want stopped on a flat battery", fr.LeftMotor.Speed, fr.RightMotor.Speed)
+	}
+	if got := p.display.GetCurrentExpression(); got != EXPR_LOW_BATTERY {
+		t.Errorf("expression = %d, want EXPR_LOW_BATTERY", got)
+	}
+
+	drainTo(t, p, fr, FAKE_FULL_BATTERY_MV, navlogic.BatteryOK)
+	runFor(p, fr, 1000)
+	if fr.LeftMotor.Speed == 0 {
+		t.Error("motors still stopped after charging")
+	}
+}
+
+func TestBattery_FlatAtPowerOn(t *testing.T) {
+	fr := NewFakeRobot()
+	fr.Battery.Mv = testFlatBatteryMv
+	p := New(fr.Robot)
+	runFor(p, fr, 5000)
+
+	if fr.LeftMotor.Speed != 0 || fr.RightMotor.Speed != 0 {
+		t.Errorf("motors = (%d, %d), want never started on a flat battery", fr.LeftMotor.Speed, fr.RightMotor.Speed)
+	}
+	if got := fr.Buzzer.Pulses; got != ERROR_CODE_FLAT_BATTERY {
+		t.Errorf("beeps = %d, want the %d-beep flat-battery code", got, ERROR_CODE_FLAT_BATTERY)
+	}
+	if got := p.display.GetCurrentExpression(); got != EXPR_LOW_BATTERY {
+		t.Errorf("expression = %d, want EXPR_LOW_BATTERY", got)
+	}
+	fr.Power.IdleMs = 0
+	p.Rest(10)
+	if fr.Power.IdleMs != navlogic.SleepCheckMs {
+		t.Errorf("flat-battery rest = %d ms, want SleepCheckMs", fr.Power.IdleMs)
+	}
+}
+
+func TestBattery_FlatWakesTheSleepingPet(t *testing.T) {
+	p, fr := asleepPet(t)
+	runFor(p, fr, navlogic.SleepScreenOnMs)
+
+	drainTo(t, p, fr, testFlatBatteryMv, navlogic.BatteryCritical)
+	if p.IsAsleep() {
+		t.Error("still asleep on a flat battery")
+	}
+	if fr.Display.Off {
+		t.Error("screen off on a flat battery")
+	}
+	if got := p.display.GetCurrentExpression(); got != EXPR_LOW_BATTERY {
+		t.Errorf("expression = %d, want EXPR_LOW_BATTERY", got)
+	}
+}
@@ -0,0 +1,168 @@
+package pet
+
+import (
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+// BehaviorPatterns drives the status LED and buzzer from pattern state machines that Update advances
+// every main-loop pass, so indication never holds up navigation.
+type BehaviorPatterns struct {
+	statusLed Indicator
+	buzzer    ToneGenerator
+	led       navlogic.BlinkPlayer
+	code      navlogic.BlinkPlayer
+	melody    navlogic.MelodyPlayer
+	ledOn     bool
+	hz        uint16
+}
+
+func NewBehaviorPatterns(statusLed Indicator, buzzer ToneGenerator) *BehaviorPatterns {
+	return &BehaviorPatterns{
+		statusLed: statusLed,
+		buzzer:    buzzer,
+	}
+}
+
+// Named sounds, in the melody format of navlogic.NextNote.
+const (
+	SOUND_HAPPY_CHIRP     = "16c7,16e7,8g7"
+	SOUND_STARTLED_SQUEAK = "32g7,16c8"
+	SOUND_EDGE_ALARM      = "32a7,32e7,32a7,32e7"
+	SOUND_SLEEPY_YAWN     = "8g5,8e5,4c5"
+	SOUND_INTRUDER_ALARM  = "16a7,16p,16a7,16p,16a7,16p,16a7,16p,16a7,16p,16a7"
+	SOUND_NERVOUS_WHIMPER = "16e6,16d#6,16e6,8d#6"
+	SOUND_BORED_SIGH      = "4e6,2c6"
+	SOUND_WAKE_UP         = "16c6,16e6,16g6,8c7"
+)
+
+// Status LED patterns, repeated for as long as the pet stays in the state.
+var (
+	LED_SLOW_BLINK   = navlogic.BlinkPattern{OnMs: 100, Count: 1, PauseMs: 1900}
+	LED_HEARTBEAT    = navlogic.BlinkPattern{OnMs: 80, OffMs: 120, Count: 2, PauseMs: 600}
+	LED_DOUBLE_FLASH = navlogic.BlinkPattern{OnMs: 60, OffMs: 60, Count: 2, PauseMs: 300}
+	LED_FAST_BLINK   = navlogic.BlinkPattern{OnMs: 100, OffMs: 100, Count: 1}
+	LED_SOLID        = navlogic.BlinkPattern{OnMs: 1000, Count: 1}
+	LED_WINK         = navlogic.BlinkPattern{OnMs: 40, Count: 1, PauseMs: 2960}
+	LED_STROBE       = navlogic.BlinkPattern{OnMs: 60, OffMs: 60, Count: 1}
+)
+
+const (
+	ERROR_BEEP_MS  = 300
+	ERROR_GAP_MS   = 200
+	ERROR_PAUSE_MS = 1000
+)
+
+// Error beep codes: the number of long beeps SoundErrorCode gives.
+const (
+	ERROR_CODE_LOW_BATTERY  = 2
+	ERROR_CODE_SONAR        = 3
+	ERROR_CODE_FLAT_BATTERY = 4
+)
+
+// IndicateStateChange starts the state's LED pattern and sound:
+//
+//	IDLE         slow blink     sleepy yawn
+//	MOVING       heartbeat      -
+//	OBSTACLE     double flash   startled squeak
+//	EDGE         fast blink     edge alarm
+//	INTERACTING  solid          happy chirp
+//	GUARDING     wink           -
+//	ALERT        strobe         intruder alarm
+//
+// States without a sound let the current one finish.
+func (bp *BehaviorPatterns) IndicateStateChange(state int) {
+	switch state {
+	case IDLE_STATE:
+		bp.indicate(LED_SLOW_BLINK, SOUND_SLEEPY_YAWN)
+	case MOVING_STATE:
+		bp.indicate(LED_HEARTBEAT, "")
+	case OBSTACLE_AVOIDANCE_STATE:
+		bp.indicate(LED_DOUBLE_FLASH, SOUND_STARTLED_SQUEAK)
+	case EDGE_AVOIDANCE_STATE:
+		bp.indicate(LED_FAST_BLINK, SOUND_EDGE_ALARM)
+	case INTERACTING_STATE:
+		bp.indicate(LED_SOLID, SOUND_HAPPY_CHIRP)
+	case GUARDING_STATE:
+		bp.indicate(LED_WINK, "")
+	case ALERT_STATE:
+		bp.indicate(LED_STROBE, SOUND_INTRUDER_ALARM)
+	}
+}
+
+// IndicateFeeling plays the sound of a mood the pet has just fallen into; contentment is silent.
+func (bp *BehaviorPatterns) IndicateFeeling(feeling int) {
+	switch feeling {
+	case navlogic.FeelingHappy:
+		bp.PlaySound(SOUND_HAPPY_CHIRP)
+	case navlogic.FeelingAfraid:
+		bp.PlaySound(SOUND_NERVOUS_WHIMPER)
+	case navlogic.FeelingBored:
+		bp.PlaySound(SOUND_BORED_SIGH)
+	case navlogic.FeelingTired:
+		bp.PlaySound(SOUND_SLEEPY_YAWN)
+	}
+}
+
+// IndicateSleep yawns and turns the LED off for the night.
+func (bp *BehaviorPatterns) IndicateSleep() {
+	bp.led.Stop()
+	bp.PlaySound(SOUND_SLEEPY_YAWN)
+}
+
+// IndicateBatteryFlat turns the LED off and beeps the flat-battery code once.
+func (bp *BehaviorPatterns) IndicateBatteryFlat() {
+	bp.led.Stop()
+	bp.SoundErrorCode(ERROR_CODE_FLAT_BATTERY)
+}
+
+func (bp *BehaviorPatterns) IndicateWake() {
+	bp.PlaySound(SOUND_WAKE_UP)
+}
+
+// Busy reports whether a sound or error code is still playing.
+func (bp *BehaviorPatterns) Busy() bool {
+	return bp.melody.Active() || bp.code.Active()
+}
+
+func (bp *BehaviorPatterns) indicate(pattern navlogic.BlinkPattern, melody string) {
+	bp.led.Start(pattern, true)
+	if melody != "" {
+		bp.PlaySound(melody)
+	}
+}
+
+// PlaySound starts a melody, replacing any that is still playing.
+func (bp *BehaviorPatterns) PlaySound(melody string) {
+	bp.melody.Start(melody)
+}
+
+// SoundErrorCode gives code long beeps in step with the LED, so a fault can be told apart without a display.
+// The code takes over the LED and buzzer until it has finished.
+func (bp *BehaviorPatterns) SoundErrorCode(code int) {
+	bp.code.Start(navlogic.BlinkPattern{OnMs: ERROR_BEEP_MS, OffMs: ERROR_GAP_MS, Count: uint8(code), PauseMs: ERROR_PAUSE_MS}, false)
+}
+
+// Update advances the patterns by elapsedMs and sets the LED and buzzer, writing them only when they change.
+func (bp *BehaviorPatterns) Update(elapsedMs uint32) {
+	ledOn := bp.led.Advance(elapsedMs)
+	hz := bp.melody.Advance(elapsedMs)
+	if codeOn := bp.code.Advance(elapsedMs); bp.code.Active() {
+		ledOn, hz = codeOn, 0
+		if codeOn {
+			hz = BEEP_HZ
+		}
+	}
+
+	if ledOn != bp.ledOn {
+		bp.ledOn = ledOn
+		if ledOn {
+			bp.statusLed.High()
+		} else {
+			bp.statusLed.Low()
+		}
+	}
+	if hz != bp.hz {
+		bp.hz = hz
+		bp.buzzer.Tone(hz)
+	}
+}
@@ -0,0 +1,107 @@
+package pet
+
+import (
+	"testing"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+func TestBehaviorPatterns_StatePatterns(t *testing.T) {
+	const windowMs, stepMs = 2000, 5
+	tests := []struct {
+		name        string
+		state       int
+		melody      string
+		wantFlashes int
+	}{
+		{"idle yawns with a slow blink", IDLE_STATE, SOUND_SLEEPY_YAWN, 1},
+		{"moving is a silent heartbeat", MOVING_STATE, "", 4},
+		{"obstacle squeaks with a double flash", OBSTACLE_AVOIDANCE_STATE, SOUND_STARTLED_SQUEAK, 8},
+		{"edge alarm with a fast blink", EDGE_AVOIDANCE_STATE, SOUND_EDGE_ALARM, 10},
+		{"interaction chirps with the LED solid", INTERACTING_STATE, SOUND_HAPPY_CHIRP, 1},
+		{"guarding is a silent wink", GUARDING_STATE, "", 1},
+		{"alert sounds the alarm with a strobe", ALERT_STATE, SOUND_INTRUDER_ALARM, 17},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			led, buzzer := &FakeIndicator{}, &FakeBuzzer{}
+			bp := NewBehaviorPatterns(led, buzzer)
+			bp.IndicateStateChange(tt.state)
+			for ms := 0; ms < windowMs; ms += stepMs {
+				bp.Update(stepMs)
+			}
+			if want := soundedNotes(tt.melody); buzzer.Pulses != want {
+				t.Errorf("notes played = %d, want %d", buzzer.Pulses, want)
+			}
+			if buzzer.Hz != 0 {
+				t.Errorf("buzzer at %d Hz after the sound, want silent", buzzer.Hz)
+			}
+			if led.Pulses != tt.wantFlashes {
+				t.Errorf("LED flashes in %d ms = %d, want %d", windowMs, led.Pulses, tt.wantFlashes)
+			}
+		})
+	}
+}
+
+func TestBehaviorPatterns_ErrorCodeTakesOverThenResumes(t *testing.T) {
+	led, buzzer := &FakeIndicator{}, &FakeBuzzer{}
+	bp := NewBehaviorPatterns(led, buzzer)
+	bp.IndicateStateChange(INTERACTING_STATE)
+	bp.SoundErrorCode(ERROR_CODE_SONAR)
+
+	codeMs := ERROR_CODE_SONAR*(ERROR_BEEP_MS+ERROR_GAP_MS) + ERROR_PAUSE_MS
+	for ms := 0; ms < codeMs; ms += 10 {
+		bp.Update(10)
+		if led.On != (buzzer.Hz == BEEP_HZ) {
+			t.Fatalf("at %d ms: LED on %v with buzzer at %d Hz, want them in step", ms, led.On, buzzer.Hz)
+		}
+	}
+	if buzzer.Pulses != ERROR_CODE_SONAR || led.Pulses != ERROR_CODE_SONAR {
+		t.Errorf("beeps = %d, flashes = %d, want %d of each", buzzer.Pulses, led.Pulses, ERROR_CODE_SONAR)
+	}
+
+	bp.Update(10)
+	if !led.On {
+		t.Error("LED off after the code, want the solid interaction pattern back")
+	}
+}
+
+func TestBehaviorPatterns_UpdateNeverBlocks(t *testing.T) {
+	led, buzzer := &FakeIndicator{}, &FakeBuzzer{}
+	bp := NewBehaviorPatterns(led, buzzer)
+	bp.IndicateStateChange(ALERT_STATE)
+	if led.On || buzzer.Hz != 0 {
+		t.Error("IndicateStateChange drove the outputs; only Update should")
+	}
+	bp.Update(0)
+	if !led.On || buzzer.Hz == 0 {
+		t.Errorf("after the first Update: LED on %v, buzzer %d Hz; want both on", led.On, buzzer.Hz)
+	}
+}
+
+func TestBehaviorPatterns_FeelingSounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		feeling int
+		melody  string
+	}{
+		{"content is silent", navlogic.FeelingContent, ""},
+		{"happy chirps", navlogic.FeelingHappy, SOUND_HAPPY_CHIRP},
+		{"afraid whimpers", navlogic.FeelingAfraid, SOUND_NERVOUS_WHIMPER},
+		{"bored sighs", navlogic.FeelingBored, SOUND_BORED_SIGH},
+		{"tired yawns", navlogic.FeelingTired, SOUND_SLEEPY_YAWN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buzzer := &FakeBuzzer{}
+			bp := NewBehaviorPatterns(&FakeIndicator{}, buzzer)
+			bp.IndicateFeeling(tt.feeling)
+			for ms := 0; ms < 2000; ms += 5 {
+				bp.Update(5)
+			}
+			if want := soundedNotes(tt.melody); buzzer.Pulses != want {
+				t.Errorf("notes played = %d, want %d", buzzer.Pulses, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package pet
+
+import (
+	"time"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/logging"
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+const (
+	CALIBRATION_PAUSE_MS      = 200
+	CALIBRATION_TICK_MS       = 20
+	CALIBRATION_FAULT_BEEP_MS = 600
+	IR_SAMPLE_MS              = 5
+	RECALIBRATE_HAND_CM       = 5
+	CALIBRATION_STORAGE_OFF   = 0
+	TRIM_RUN_MS               = 1500
+	TRIM_MAX_ROUNDS           = 8
+	TRIM_ANSWER_MS            = 3000
+	TRIM_SAMPLE_MS            = 50
+	TRIM_ANSWER_SAMPLES       = 3
+)
+
+type CalibrationModule struct {
+	robot           *Robot
+	sensorModule    *SensorModule
+	motorController *MotorController
+	calibrated      bool
+	irStatus        [IR_SENSOR_COUNT]int
+}
+
+func NewCalibrationModule(robot *Robot, sensorModule *SensorModule, motorController *MotorController) *CalibrationModule {
+	return &CalibrationModule{
+		robot:           robot,
+		sensorModule:    sensorModule,
+		motorController: motorController,
+		calibrated:      false,
+	}
+}
+
+func (cm *CalibrationModule) IsCalibrated() bool {
+	return cm.calibrated
+}
+
+// IRSensorStatus returns the navlogic.EdgeSensor* result of the last IR calibration for each slot.
+func (cm *CalibrationModule) IRSensorStatus() [IR_SENSOR_COUNT]int {
+	return cm.irStatus
+}
+
+// AdjustThresholds tunes the obstacle distance (cm) and every IR edge threshold at runtime.
+func (cm *CalibrationModule) AdjustThresholds(obstacleCm int, edge uint16) {
+	cm.sensorModule.SetThresholds(obstacleCm, edge)
+}
+
+func (cm *CalibrationModule) CalibrateSensors() {
+	cm.robot.BlinkLED(2)
+	logging.Info(logging.Calib, "Starting sensor calibration")
+	distance := cm.sensorModule.ReadUltrasonicDistance()
+	logging.Info(logging.Calib, "Baseline distance", distance)
+	time.Sleep(time.Millisecond * CALIBRATION_PAUSE_MS)
+	if cm.CalibrateEdgeThresholds() {
+		logging.Info(logging.Calib, "Sensor calibration complete")
+		cm.robot.Beep(time.Millisecond * 50)
+	} else {
+		logging.Warn(logging.Calib, "Sensor calibration found a faulty IR sensor")
+		cm.robot.Beep(time.Millisecond * CALIBRATION_FAULT_BEEP_MS)
+	}
+}
+
+// CalibrateEdgeThresholds samples the fitted IR sensors on the table surface and gives each its own edge threshold.
+// A sensor already over an edge keeps its previous threshold; a disconnected one is disabled (threshold 0).
+// It reports whether every fitted sensor calibrated cleanly.
+func (cm *CalibrationModule) CalibrateEdgeThresholds() bool {
+	var samples [IR_SENSOR_COUNT][navlogic.EdgeCalSamples]uint16
+	for n := 0; n < navlogic.EdgeCalSamples; n++ {
+		raw := cm.sensorModule.ReadIRRaw()
+		for i := range raw {
+			samples[i][n] = raw[i]
+		}
+		time.Sleep(time.Millisecond * IR_SAMPLE_MS)
+	}
+
+	_, thresholds := cm.sensorModule.GetThresholds()
+	cm.calibrated = true
+	for i := range samples {
+		cm.irStatus[i] = navlogic.EdgeSensorOK
+		if !cm.sensorModule.HasIRSensor(i) {
+			continue
+		}
+		threshold, status := navlogic.CalibrateEdgeSensor(samples[i][:], thresholds[i])
+		cm.irStatus[i] = status
+		switch status {
+		case navlogic.EdgeSensorOK:
+			logging.Info(logging.Calib, "IR sensor threshold", i, int(threshold))
+		case navlogic.EdgeSensorOverEdge:
+			logging.Warn(logging.Calib, "IR sensor over an edge", i)
+			cm.calibrated = false
+		case navlogic.EdgeSensorDisconnected:
+			logging.Error(logging.Calib, "IR sensor disconnected", i)
+			threshold = 0
+			cm.calibrated = false
+		}
+		thresholds[i] = threshold
+	}
+	cm.sensorModule.SetEdgeThresholds(thresholds)
+	return cm.calibrated
+}
+
+// CalibrateMotors runs the user-guided trim procedure. The pet drives forward and back, stops and beeps;
+// the user then holds a hand in front of the ultrasonic sensor to say how it veered (navlogic.TrimAnswer).
+// Each answer moves the trim one step and is acknowledged with one beep (veered left) or two (veered right);
+// no hand within TRIM_ANSWER_MS means it drove straight and ends the procedure.
+func (cm *CalibrationModule) CalibrateMotors() {
+	cm.robot.BlinkLED(3)
+	logging.Info(logging.Calib, "Starting motor trim")
+	for round := 0; round < TRIM_MAX_ROUNDS; round++ {
+		cm.motorController.MoveFor(MOVE_FORWARD, TRIM_RUN_MS)
+		cm.runMotion()
+		cm.motorController.MoveFor(MOVE_BACKWARD, TRIM_RUN_MS)
+		cm.runMotion()
+		cm.robot.Beep(time.Millisecond * 25)
+
+		answer := cm.waitTrimAnswer()
+		if answer == navlogic.TrimStraight {
+			break
+		}
+		left, right := cm.motorController.GetTrim()
+		left, right = navlogic.AdjustTrim(left, right, answer)
+		cm.motorController.SetTrim(left, right)
+		logging.Info(logging.Calib, "Motor trim", left, right)
+
+		beeps := 1
+		if answer == navlogic.TrimVeeredRight {
+			beeps = 2
+		}
+		for i := 0; i < beeps; i++ {
+			cm.robot.Beep(time.Millisecond * 80)
+			time.Sleep(time.Millisecond * 120)
+		}
+	}
+	logging.Info(logging.Calib, "Motor calibration complete")
+	cm.robot.Beep(time.Millisecond * 25)
+}
+
+// waitTrimAnswer waits up to TRIM_ANSWER_MS for a hand held in one answer zone for TRIM_ANSWER_SAMPLES reads in a row.
+func (cm *CalibrationModule) waitTrimAnswer() int {
+	start := cm.robot.Clock.Millis()
+	last, count := navlogic.TrimStraight, 0
+	for cm.robot.Clock.Millis()-start < TRIM_ANSWER_MS {
+		answer := navlogic.TrimAnswer(cm.sensorModule.ReadUltrasonicDistance())
+		if answer == last {
+			count++
+		} else {
+			last, count = answer, 1
+		}
+		if answer != navlogic.TrimStraight && count >= TRIM_ANSWER_SAMPLES {
+			return answer
+		}
+		time.Sleep(time.Millisecond * TRIM_SAMPLE_MS)
+	}
+	return navlogic.TrimStraight
+}
+
+// runMotion drives the motor controller against the robot clock until the timed move finishes; calibration runs before the main loop.
+// It stops early if an edge shows up in the direction of travel.
+func (cm *CalibrationModule) runMotion() {
+	last := cm.robot.Clock.Millis()
+	for cm.motorController.IsMoving() {
+		step, _ := cm.motorController.CurrentMotion()
+		if (step.Linear > 0 && cm.sensorModule.IsEdgeDetected()) || (step.Linear < 0 && cm.sensorModule.IsRearEdgeDetected()) {
+			cm.motorController.Stop()
+			return
+		}
+		time.Sleep(time.Millisecond * CALIBRATION_TICK_MS)
+		now := cm.robot.Clock.Millis()
+		cm.motorController.Update(now - last)
+		last = now
+	}
+}
+
+// LoadCalibration applies the record saved in the robot's storage; it fails if there is no storage
+// or the record is missing, from another firmware version, or corrupt.
+func (cm *CalibrationModule) LoadCalibration() error {
+	if cm.robot.Storage == nil {
+		return navlogic.ErrRecordMissing
+	}
+	var buf [navlogic.CalibrationRecordSize]byte
+	if _, err := cm.robot.Storage.ReadAt(buf[:], CALIBRATION_STORAGE_OFF); err != nil {
+		return err
+	}
+	record, err := navlogic.DecodeCalibrationRecord(&buf)
+	if err != nil {
+		return err
+	}
+	cm.sensorModule.SetEdgeThresholds(record.IRThresholds)
+	cm.sensorModule.SetObstacleThreshold(int(record.ObstacleCm))
+	cm.sensorModule.SetDistanceOffset(int(record.UltrasonicOffset))
+	cm.motorController.SetTrim(int(record.LeftTrim), int(record.RightTrim))
+	cm.calibrated = true
+	return nil
+}
+
+// SaveCalibration writes the current IR and obstacle thresholds, ultrasonic offset and motor trim to the robot's storage.
+func (cm *CalibrationModule) SaveCalibration() error {
+	if cm.robot.Storage == nil {
+		return nil
+	}
+	obstacleCm, thresholds := cm.sensorModule.GetThresholds()
+	leftTrim, rightTrim := cm.motorController.GetTrim()
+	record := navlogic.CalibrationRecord{
+		IRThresholds:     thresholds,
+		UltrasonicOffset: int8(cm.sensorModule.GetDistanceOffset()),
+		LeftTrim:         int8(leftTrim),
+		RightTrim:        int8(rightTrim),
+		ObstacleCm:       uint16(obstacleCm),
+	}
+	var buf [navlogic.CalibrationRecordSize]byte
+	record.Encode(&buf)
+	_, err := cm.robot.Storage.WriteAt(buf[:], CALIBRATION_STORAGE_OFF)
+	return err
+}
+
+// Recalibrate runs the full calibration and saves it when every sensor calibrated cleanly.
+func (cm *CalibrationModule) Recalibrate() {
+	cm.CalibrateComplete()
+	if !cm.calibrated {
+		logging.Warn(logging.Calib, "Calibration not saved: sensor fault")
+		return
+	}
+	if err := cm.SaveCalibration(); err != nil {
+		logging.Err(logging.LevelError, logging.Calib, "Calibration save failed", err)
+	}
+}
+
+func (cm *CalibrationModule) CalibrateComplete() {
+	logging.Info(logging.Calib, "Starting complete calibration")
+	cm.CalibrateSensors()
+	cm.CalibrateMotors()
+	logging.Info(logging.Calib, "Complete calibration finished")
+	cm.robot.BlinkLED(5)
+}
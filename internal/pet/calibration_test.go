@@ -0,0 +1,203 @@
+package pet
+
+import (
+	"testing"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+// floatingSensor mimics an unconnected analog input whose readings wander.
+type floatingSensor struct {
+	n int
+}
+
+func (s *floatingSensor) Get() uint16 {
+	s.n++
+	return uint16(s.n * 7919 % 0xFFFF)
+}
+
+func TestCalibrateEdgeThresholds(t *testing.T) {
+	surfaceThreshold := uint16(FAKE_SURFACE_READING * (100 - navlogic.EdgeMarginPercent) / 100)
+	tests := []struct {
+		name       string
+		setup      func(fr *FakeRobot)
+		wantOK     bool
+		wantStatus [IR_SENSOR_COUNT]int
+		want       [IR_SENSOR_COUNT]uint16
+	}{
+		{
+			"all on the surface",
+			func(fr *FakeRobot) {},
+			true,
+			[IR_SENSOR_COUNT]int{},
+			[IR_SENSOR_COUNT]uint16{surfaceThreshold, surfaceThreshold, surfaceThreshold, surfaceThreshold},
+		},
+		{
+			"front right over an edge keeps the default",
+			func(fr *FakeRobot) { fr.IRSensors[IR_FRONT_RIGHT].Value = 20 },
+			false,
+			[IR_SENSOR_COUNT]int{IR_FRONT_RIGHT: navlogic.EdgeSensorOverEdge},
+			[IR_SENSOR_COUNT]uint16{surfaceThreshold, EDGE_DETECTION_THRESHOLD, surfaceThreshold, surfaceThreshold},
+		},
+		{
+			"disconnected rear sensor is disabled",
+			func(fr *FakeRobot) { fr.Robot.IRSensors[IR_REAR_LEFT] = &floatingSensor{} },
+			false,
+			[IR_SENSOR_COUNT]int{IR_REAR_LEFT: navlogic.EdgeSensorDisconnected},
+			[IR_SENSOR_COUNT]uint16{surfaceThreshold, surfaceThreshold, 0, surfaceThreshold},
+		},
+		{
+			"rear sensors not fitted",
+			func(fr *FakeRobot) {
+				fr.Robot.IRSensors[IR_REAR_LEFT] = nil
+				fr.Robot.IRSensors[IR_REAR_RIGHT] = nil
+			},
+			true,
+			[IR_SENSOR_COUNT]int{},
+			[IR_SENSOR_COUNT]uint16{surfaceThreshold, surfaceThreshold, EDGE_DETECTION_THRESHOLD, EDGE_DETECTION_THRESHOLD},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fr := NewFakeRobot()
+			tt.setup(fr)
+			p := New(fr.Robot)
+
+			if got := p.calibration.CalibrateEdgeThresholds(); got != tt.wantOK {
+				t.Errorf("CalibrateEdgeThresholds = %v, want %v", got, tt.wantOK)
+			}
+			if got := p.calibration.IRSensorStatus(); got != tt.wantStatus {
+				t.Errorf("IRSensorStatus = %v, want %v", got, tt.wantStatus)
+			}
+			if _, got := p.sensors.GetThresholds(); got != tt.want {
+				t.Errorf("thresholds = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalibratedThresholdsDetectEdges(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	p.calibration.CalibrateEdgeThresholds()
+
+	fr.IRSensors[IR_FRONT_LEFT].Value = FAKE_SURFACE_READING / 3
+	if !p.sensors.IsEdgeDetected() {
+		t.Error("a third of the surface reading is not an edge after calibration")
+	}
+	if p.sensors.IsRearEdgeDetected() {
+		t.Error("rear edge reported with rear sensors on the surface")
+	}
+}
+
+func TestCalibration_SaveAndLoad(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	if err := p.calibration.LoadCalibration(); err != navlogic.ErrRecordMissing {
+		t.Fatalf("LoadCalibration on erased storage = %v, want ErrRecordMissing", err)
+	}
+
+	p.calibration.CalibrateEdgeThresholds()
+	p.sensors.SetDistanceOffset(-2)
+	p.motors.SetTrim(-4, 0)
+	p.sensors.SetObstacleThreshold(35)
+	if err := p.calibration.SaveCalibration(); err != nil {
+		t.Fatalf("SaveCalibration: %v", err)
+	}
+	_, saved := p.sensors.GetThresholds()
+
+	rebooted := New(fr.Robot)
+	if err := rebooted.calibration.LoadCalibration(); err != nil {
+		t.Fatalf("LoadCalibration after save: %v", err)
+	}
+	if obstacle, got := rebooted.sensors.GetThresholds(); obstacle != 35 || got != saved {
+		t.Errorf("loaded thresholds = %d, %v; want 35, %v", obstacle, got, saved)
+	}
+	if got := rebooted.sensors.GetDistanceOffset(); got != -2 {
+		t.Errorf("loaded distance offset = %d, want -2", got)
+	}
+	if l, r := rebooted.motors.GetTrim(); l != -4 || r != 0 {
+		t.Errorf("loaded trim = (%d, %d), want (-4, 0)", l, r)
+	}
+	if !rebooted.calibration.IsCalibrated() {
+		t.Error("not calibrated after loading a saved record")
+	}
+
+	fr.Storage.Data[CALIBRATION_STORAGE_OFF+6] ^= 0xFF
+	if err := New(fr.Robot).calibration.LoadCalibration(); err != navlogic.ErrRecordChecksum {
+		t.Errorf("LoadCalibration of corrupt record = %v, want ErrRecordChecksum", err)
+	}
+}
+
+func TestSensorModule_DistanceOffset(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    int
+		offset int
+		want   int
+	}{
+		{"no offset", 50, 0, 50},
+		{"positive offset", 50, 3, 53},
+		{"negative offset", 50, -4, 46},
+		{"clamped at zero", 2, -4, 0},
+		{"timeout unchanged", navlogic.TimeoutDistance, 3, navlogic.TimeoutDistance},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fr := NewFakeRobot()
+			fr.Ultrasonic.Distance = tt.raw
+			s := NewSensorModule(fr.Ultrasonic, &fr.Robot.IRSensors)
+			s.SetDistanceOffset(tt.offset)
+			if got := s.ReadUltrasonicDistance(); got != tt.want {
+				t.Errorf("ReadUltrasonicDistance = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSensorModule_ObstacleHysteresis(t *testing.T) {
+	fr := NewFakeRobot()
+	s := NewSensorModule(fr.Ultrasonic, &fr.Robot.IRSensors)
+	steps := []struct {
+		distance int
+		want     bool
+	}{
+		{OBSTACLE_DISTANCE_THRESHOLD + 2, false},
+		{OBSTACLE_DISTANCE_THRESHOLD - 6, false},
+		{OBSTACLE_DISTANCE_THRESHOLD - 6, true},
+		{OBSTACLE_DISTANCE_THRESHOLD + 2, true},
+		{OBSTACLE_DISTANCE_THRESHOLD + 2, true},
+		{OBSTACLE_DISTANCE_THRESHOLD + 12, true},
+		{OBSTACLE_DISTANCE_THRESHOLD + 12, false},
+	}
+	for i, step := range steps {
+		fr.Ultrasonic.Distance = step.distance
+		if got := s.IsObstacleDetected(); got != step.want {
+			t.Fatalf("reading %d (%d cm): IsObstacleDetected = %v, want %v", i, step.distance, got, step.want)
+		}
+	}
+}
+
+func TestCalibration_TrimAnswer(t *testing.T) {
+	tests := []struct {
+		name     string
+		distance int
+		want     int
+	}{
+		{"hand close", 5, navlogic.TrimVeeredLeft},
+		{"hand further away", 15, navlogic.TrimVeeredRight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fr := NewFakeRobot()
+			fr.Ultrasonic.Distance = tt.distance
+			p := New(fr.Robot)
+			if got := p.calibration.waitTrimAnswer(); got != tt.want {
+				t.Errorf("waitTrimAnswer = %d, want %d", got, tt.want)
+			}
+			if fr.Ultrasonic.Reads != TRIM_ANSWER_SAMPLES {
+				t.Errorf("answered after %d reads, want %d", fr.Ultrasonic.Reads, TRIM_ANSWER_SAMPLES)
+			}
+		})
+	}
+}
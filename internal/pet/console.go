@@ -0,0 +1,171 @@
+package pet
+
+import (
+	"errors"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/console"
+	"github.com/GyeongHoKim/tiny-pet/internal/logging"
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+var errBatteryFlat = errors.New("battery flat")
+
+// consoleHelp is the reply to "help"; see package console for the syntax.
+var consoleHelp = [...]string{
+	"sensors | thresholds | calibrate | save | stop",
+	"mode [walk|guard|interactive]",
+	"drive <linear> <angular> [ms]",
+	"face <name|0-9>",
+	"threshold obstacle <cm>",
+	"threshold edge [sensor] <raw>",
+	"threshold offset <cm>",
+	"telemetry on|off | log",
+}
+
+// serviceConsole runs the commands that have arrived on the serial port. It only reads what is already
+// buffered, so it never holds up the main loop; each command is answered with "ok" or "error: ...".
+func (p *Pet) serviceConsole() {
+	port := p.robot.Serial
+	if port == nil {
+		return
+	}
+	for port.Buffered() > 0 {
+		b, err := port.ReadByte()
+		if err != nil {
+			return
+		}
+		line, err := p.lines.Feed(b)
+		if err == nil && line == nil {
+			continue
+		}
+		var cmd console.Command
+		if err == nil {
+			cmd, err = console.Parse(line)
+		}
+		if err == nil {
+			err = p.runCommand(cmd)
+		}
+		if err != nil {
+			p.out.Str("error: ").Str(err.Error()).End()
+		} else {
+			p.out.Str("ok").End()
+		}
+	}
+}
+
+// runCommand carries out a parsed console command; a command wakes a sleeping pet first.
+func (p *Pet) runCommand(cmd console.Command) error {
+	if p.asleep {
+		p.wake()
+	}
+	switch cmd.Kind {
+	case console.CmdHelp:
+		for _, line := range consoleHelp {
+			p.out.Str(line).End()
+		}
+	case console.CmdSensors:
+		p.printSensors()
+	case console.CmdMode:
+		if cmd.NArgs == 0 {
+			p.out.Str("mode=").Str(console.ModeNames[p.navigation.GetBehaviorMode()]).
+				Str(" state=").Str(console.StateNames[p.navigation.GetCurrentState()]).
+				Str(" manual=").Bool(p.manual).End()
+			return nil
+		}
+		p.manual = false
+		p.navigation.SetBehaviorMode(cmd.Args[0])
+		p.lastState = -1
+	case console.CmdDrive:
+		if p.isBatteryFlat() {
+			return errBatteryFlat
+		}
+		p.takeControl()
+		if cmd.NArgs == 3 {
+			p.motors.StartMotion(navlogic.MotionStep{Linear: cmd.Args[0], Angular: cmd.Args[1], DurationMs: uint32(cmd.Args[2])})
+		} else {
+			p.motors.Drive(cmd.Args[0], cmd.Args[1])
+		}
+	case console.CmdStop:
+		p.takeControl()
+	case console.CmdCalibrate:
+		p.takeControl()
+		p.calibration.CalibrateComplete()
+		// Calibration blocks; do not count its time as a main-loop pass.
+		p.lastTickMs = p.robot.Clock.Millis()
+		p.printThresholds()
+	case console.CmdSave:
+		return p.calibration.SaveCalibration()
+	case console.CmdFace:
+		p.display.ShowExpression(cmd.Args[0])
+	case console.CmdThresholds:
+		p.printThresholds()
+	case console.CmdSetObstacle:
+		p.sensors.SetObstacleThreshold(cmd.Args[0])
+	case console.CmdSetEdge:
+		_, edge := p.sensors.GetThresholds()
+		if cmd.NArgs == 1 {
+			for i := range edge {
+				edge[i] = uint16(cmd.Args[0])
+			}
+		} else if cmd.Args[0] < IR_SENSOR_COUNT {
+			edge[cmd.Args[0]] = uint16(cmd.Args[1])
+		} else {
+			return console.ErrBadArgument
+		}
+		p.sensors.SetEdgeThresholds(edge)
+	case console.CmdSetOffset:
+		p.sensors.SetDistanceOffset(cmd.Args[0])
+	case console.CmdTelemetry:
+		p.SetTelemetry(cmd.Args[0] == 1)
+	case console.CmdLog:
+		return logging.Dump(p.robot.Serial)
+	}
+	return nil
+}
+
+// takeControl stops the motors and pauses navigation until a mode command hands control back.
+func (p *Pet) takeControl() {
+	p.manual = true
+	p.navigation.EmergencyStop()
+}
+
+// driveManually runs a console drive in place of navigation; a front edge still stops a forward drive
+// and a rear edge, when rear sensors are fitted, a reverse.
+func (p *Pet) driveManually(elapsed uint32) {
+	p.motors.Update(elapsed)
+	left, right := p.motors.GetWheelSpeeds()
+	if left+right > 0 && p.sensors.IsEdgeDetected() || left+right < 0 && p.sensors.IsRearEdgeDetected() {
+		p.motors.Stop()
+	}
+}
+
+func (p *Pet) printSensors() {
+	p.out.Str("dist=").Int(p.sensors.ReadUltrasonicDistance()).Str(" ir=")
+	for i, v := range p.sensors.ReadIRRaw() {
+		if i > 0 {
+			p.out.Str(",")
+		}
+		p.out.Int(int(v))
+	}
+	p.out.Str(" edge=")
+	for _, edge := range p.sensors.ReadIRSensors() {
+		p.out.Bool(edge)
+	}
+	if p.robot.Battery != nil {
+		p.out.Str(" batt=").Int(int(p.robot.Battery.Millivolts())).Str("mV")
+	}
+	p.out.End()
+}
+
+func (p *Pet) printThresholds() {
+	obstacle, edge := p.sensors.GetThresholds()
+	p.out.Str("obstacle=").Int(obstacle).Str(" edge=")
+	for i, v := range edge {
+		if i > 0 {
+			p.out.Str(",")
+		}
+		p.out.Int(int(v))
+	}
+	p.out.Str(" offset=").Int(p.sensors.GetDistanceOffset())
+	p.out.End()
+}
@@ -0,0 +1,237 @@
+package pet
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/console"
+	"github.com/GyeongHoKim/tiny-pet/internal/logging"
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+// command types line into the console, runs one Tick and returns the reply lines.
+func command(p *Pet, fr *FakeRobot, line string) []string {
+	fr.Serial.Out.Reset()
+	fr.Serial.Type(line)
+	p.Tick()
+	return strings.Split(strings.TrimSuffix(fr.Serial.Out.String(), "\r\n"), "\r\n")
+}
+
+func TestConsole_NameTablesMatchConstants(t *testing.T) {
+	if got, want := len(console.FaceNames), EXPR_LOW_BATTERY+1; got != want {
+		t.Errorf("len(console.FaceNames) = %d, want %d", got, want)
+	}
+	if got, want := len(console.ModeNames), INTERACTIVE_MODE+1; got != want {
+		t.Errorf("len(console.ModeNames) = %d, want %d", got, want)
+	}
+	if got, want := len(console.StateNames), ALERT_STATE+1; got != want {
+		t.Errorf("len(console.StateNames) = %d, want %d", got, want)
+	}
+}
+
+func TestConsole_Replies(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"sensors", []string{"dist=-1 ir=65535,65535,65535,65535 edge=0000 batt=4200mV", "ok"}},
+		{"thresholds", []string{"obstacle=20 edge=500,500,500,500 offset=0", "ok"}},
+		{"mode", []string{"mode=walk state=moving manual=0", "ok"}},
+		{"dance", []string{"error: unknown command"}},
+		{"drive 120 0", []string{"error: bad argument"}},
+		{"threshold edge 4 100", []string{"error: bad argument"}},
+		{"save", []string{"ok"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			fr := NewFakeRobot()
+			p := New(fr.Robot)
+			if got := command(p, fr, tt.line); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("reply = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsole_Log(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	if logging.Dump(io.Discard) == logging.ErrNoRing {
+		if got := command(p, fr, "log"); strings.Join(got, "|") != "error: no log buffer" {
+			t.Errorf("reply = %q without the log_ring tag", got)
+		}
+		return
+	}
+
+	logging.Reset()
+	logging.Error(logging.Calib, "Test line", 7)
+	got := command(p, fr, "log")
+	if got[len(got)-1] != "ok" {
+		t.Fatalf("reply = %q, want the kept lines then ok", got)
+	}
+	if logging.Enabled(logging.LevelError, logging.Calib) && got[0] != "E calib: Test line 7" {
+		t.Errorf("reply = %q, want the logged line first", got)
+	}
+}
+
+func TestConsole_Help(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	reply := command(p, fr, "help")
+	if len(reply) != len(consoleHelp)+1 || reply[len(reply)-1] != "ok" {
+		t.Errorf("help reply = %q", reply)
+	}
+}
+
+func TestConsole_LinesSplitAcrossTicks(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	fr.Serial.In = []byte("mo")
+	p.Tick()
+	if fr.Serial.Out.Len() != 0 {
+		t.Fatalf("reply before the line ended: %q", fr.Serial.Out.String())
+	}
+	if got := command(p, fr, "de guard"); got[0] != "ok" {
+		t.Fatalf("reply = %q, want ok", got)
+	}
+	if got := p.navigation.GetBehaviorMode(); got != GUARD_MODE {
+		t.Errorf("mode = %d, want GUARD_MODE", got)
+	}
+}
+
+func TestConsole_ModeSwitch(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	command(p, fr, "mode interactive")
+	if got := p.navigation.GetBehaviorMode(); got != INTERACTIVE_MODE {
+		t.Errorf("mode = %d, want INTERACTIVE_MODE", got)
+	}
+	command(p, fr, "mode walk")
+	runFor(p, fr, 1000)
+	if got := p.navigation.GetCurrentState(); got != MOVING_STATE {
+		t.Errorf("state = %d, want MOVING_STATE", got)
+	}
+}
+
+func TestConsole_ManualDrive(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	// Something close ahead would make navigation back away; manual driving ignores it.
+	fr.Ultrasonic.Distance = 5
+
+	command(p, fr, "drive 50 0")
+	runFor(p, fr, 1000)
+	if fr.LeftMotor.Speed != 50 || fr.RightMotor.Speed != 50 {
+		t.Errorf("motors = (%d, %d), want (50, 50)", fr.LeftMotor.Speed, fr.RightMotor.Speed)
+	}
+	if got := command(p, fr, "mode"); got[0] != "mode=walk state=idle manual=1" {
+		t.Errorf("mode reply = %q", got[0])
+	}
+
+	command(p, fr, "stop")
+	if fr.LeftMotor.Speed != 0 || fr.RightMotor.Speed != 0 {
+		t.Errorf("motors = (%d, %d) after stop, want (0, 0)", fr.LeftMotor.Speed, fr.RightMotor.Speed)
+	}
+	runFor(p, fr, 1000)
+	if fr.LeftMotor.Speed != 0 || fr.RightMotor.Speed != 0 {
+		t.Errorf("motors = (%d, %d), want stopped until a mode command", fr.LeftMotor.Speed, fr.RightMotor.Speed)
+	}
+}
+
+func TestConsole_TimedDrive(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+
+	command(p, fr, "drive -40 0 500")
+	runFor(p, fr, 300)
+	if fr.LeftMotor.Speed != -40 {
+		t.Errorf("left motor = %d during the drive, want -40", fr.LeftMotor.Speed)
+	}
+	runFor(p, fr, 500)
+	if fr.LeftMotor.Speed != 0 || fr.RightMotor.Speed != 0 {
+		t.Errorf("motors = (%d, %d) after the drive, want (0, 0)", fr.LeftMotor.Speed, fr.RightMotor.Speed)
+	}
+}
+
+func TestConsole_ManualDriveStopsAtEdge(t *testing.T) {
+	tests := []struct {
+		name   string
+		drive  string
+		sensor int
+		away   string
+		speed  int
+	}{
+		{"forward onto a front edge", "drive 50 0", IR_FRONT_LEFT, "drive -50 0", -50},
+		{"reverse onto a rear edge", "drive -50 0", IR_REAR_RIGHT, "drive 50 0", 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fr := NewFakeRobot()
+			p := New(fr.Robot)
+			command(p, fr, tt.drive)
+			runFor(p, fr, 500)
+
+			fr.IRSensors[tt.sensor].Value = 0
+			runFor(p, fr, 200)
+			if fr.LeftMotor.Speed != 0 || fr.RightMotor.Speed != 0 {
+				t.Errorf("motors = (%d, %d) at an edge, want (0, 0)", fr.LeftMotor.Speed, fr.RightMotor.Speed)
+			}
+
+			command(p, fr, tt.away)
+			runFor(p, fr, 500)
+			if fr.LeftMotor.Speed != tt.speed {
+				t.Errorf("left motor = %d, want %d away from the edge", fr.LeftMotor.Speed, tt.speed)
+			}
+		})
+	}
+}
+
+func TestConsole_FaceAndThresholds(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+
+	command(p, fr, "face sick")
+	if got := p.display.GetCurrentExpression(); got != EXPR_SICK {
+		t.Errorf("expression = %d, want EXPR_SICK", got)
+	}
+
+	command(p, fr, "threshold obstacle 30")
+	command(p, fr, "threshold edge 700")
+	command(p, fr, "threshold edge 2 650")
+	obstacle, edge := p.sensors.GetThresholds()
+	if obstacle != 30 || edge != [IR_SENSOR_COUNT]uint16{700, 700, 650, 700} {
+		t.Errorf("thresholds = %d, %v; want 30, [700 700 650 700]", obstacle, edge)
+	}
+
+	command(p, fr, "threshold offset -2")
+	if got := p.sensors.GetDistanceOffset(); got != -2 {
+		t.Errorf("distance offset = %d, want -2", got)
+	}
+}
+
+func TestConsole_WakesTheSleepingPet(t *testing.T) {
+	p, fr := asleepPet(t)
+	command(p, fr, "sensors")
+	if p.IsAsleep() {
+		t.Error("still asleep after a command")
+	}
+}
+
+func TestConsole_NoDriveOnFlatBattery(t *testing.T) {
+	fr := NewFakeRobot()
+	fr.Battery.Mv = testFlatBatteryMv
+	p := New(fr.Robot)
+	p.Tick()
+	if p.BatteryLevel() != navlogic.BatteryCritical {
+		t.Fatalf("battery level = %d, want BatteryCritical", p.BatteryLevel())
+	}
+
+	if got := command(p, fr, "drive 50 0"); got[0] != "error: battery flat" {
+		t.Errorf("reply = %q, want the flat battery error", got)
+	}
+	runFor(p, fr, 500)
+	if fr.LeftMotor.Speed != 0 {
+		t.Errorf("left motor = %d on a flat battery, want 0", fr.LeftMotor.Speed)
+	}
+}
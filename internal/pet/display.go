@@ -0,0 +1,165 @@
+package pet
+
+import (
+	"github.com/GyeongHoKim/tiny-pet/internal/logging"
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+const (
+	EXPR_NEUTRAL = iota
+	EXPR_HAPPY
+	EXPR_SURPRISED
+	EXPR_SCARED
+	EXPR_EXCITED
+	EXPR_BLINK
+	EXPR_SICK
+	EXPR_SLEEPY
+	EXPR_SLEEPING
+	EXPR_LOW_BATTERY
+)
+
+const (
+	blinkInterval uint8 = 40
+	blinkDuration uint8 = 2
+)
+
+// DisplayModule drives the SSD1306 OLED and face expressions.
+type DisplayModule struct {
+	device       FaceRenderer
+	layout       faceLayout
+	currentExpr  int
+	animCounter  uint8
+	blinkCounter uint8
+	isBlinking   bool
+	zzz          int
+}
+
+// NewDisplayModule draws on device, a panel of the given geometry; faces are scaled to fit it.
+func NewDisplayModule(device FaceRenderer, geometry DisplayGeometry) *DisplayModule {
+	geometry = geometry.withDefaults()
+	if geometry.Rotation != ROTATE_0 {
+		device = &rotatedRenderer{FaceRenderer: device, geometry: geometry}
+	}
+	return &DisplayModule{
+		device:      device,
+		layout:      newFaceLayout(geometry.FaceSize()),
+		currentExpr: EXPR_NEUTRAL,
+	}
+}
+
+func (dm *DisplayModule) ShowExpression(expr int) {
+	if expr != dm.currentExpr && expr != EXPR_BLINK {
+		logging.Trace(logging.Display, "Expression", expr)
+	}
+	dm.currentExpr = expr
+	dm.device.ClearBuffer()
+	switch expr {
+	case EXPR_NEUTRAL:
+		drawNeutralFace(dm.device, &dm.layout)
+	case EXPR_HAPPY:
+		drawHappyFace(dm.device, &dm.layout)
+	case EXPR_SURPRISED:
+		drawSurprisedFace(dm.device, &dm.layout)
+	case EXPR_SCARED:
+		drawScaredFace(dm.device, &dm.layout)
+	case EXPR_EXCITED:
+		drawExcitedFace(dm.device, &dm.layout)
+	case EXPR_BLINK:
+		drawBlinkFace(dm.device, &dm.layout)
+	case EXPR_SICK:
+		drawSickFace(dm.device, &dm.layout)
+	case EXPR_SLEEPY:
+		drawSleepyFace(dm.device, &dm.layout)
+	case EXPR_SLEEPING:
+		drawSleepingFace(dm.device, &dm.layout)
+		drawZzz(dm.device, &dm.layout, dm.zzz)
+	case EXPR_LOW_BATTERY:
+		drawLowBatteryFace(dm.device, &dm.layout)
+	}
+	dm.device.Display()
+}
+
+func (dm *DisplayModule) GetCurrentExpression() int {
+	return dm.currentExpr
+}
+
+func (dm *DisplayModule) ShowStateExpression(state int) {
+	var expr int
+	switch state {
+	case IDLE_STATE:
+		expr = EXPR_NEUTRAL
+	case MOVING_STATE:
+		expr = EXPR_HAPPY
+	case OBSTACLE_AVOIDANCE_STATE:
+		expr = EXPR_SURPRISED
+	case EDGE_AVOIDANCE_STATE:
+		expr = EXPR_SCARED
+	case INTERACTING_STATE:
+		expr = EXPR_EXCITED
+	case GUARDING_STATE:
+		expr = EXPR_NEUTRAL
+	case ALERT_STATE:
+		expr = EXPR_SURPRISED
+	default:
+		expr = EXPR_NEUTRAL
+	}
+	dm.ShowExpression(expr)
+}
+
+// ShowSleeping shows the sleeping face with zzz Zs floating up from it.
+func (dm *DisplayModule) ShowSleeping(zzz int) {
+	dm.zzz = zzz
+	dm.ShowExpression(EXPR_SLEEPING)
+}
+
+// SetScreen switches the OLED panel on or off.
+func (dm *DisplayModule) SetScreen(on bool) {
+	dm.device.SetPower(on)
+}
+
+// ShowMoodExpression shows how the pet feels while it is calm (moving, idle or guarding);
+// obstacles, edges, play and intruders keep their state face.
+func (dm *DisplayModule) ShowMoodExpression(state, feeling int) {
+	switch {
+	case !isCalmState(state) || feeling == navlogic.FeelingContent:
+		dm.ShowStateExpression(state)
+	case feeling == navlogic.FeelingHappy:
+		dm.ShowExpression(EXPR_HAPPY)
+	case feeling == navlogic.FeelingAfraid:
+		dm.ShowExpression(EXPR_SCARED)
+	case feeling == navlogic.FeelingTired:
+		dm.ShowExpression(EXPR_SLEEPY)
+	default:
+		dm.ShowExpression(EXPR_NEUTRAL)
+	}
+}
+
+// isCalmState reports whether the pet is moving, idle or guarding, with nothing to react to.
+func isCalmState(state int) bool {
+	return state == IDLE_STATE || state == MOVING_STATE || state == GUARDING_STATE
+}
+
+func (dm *DisplayModule) UpdateAnimation() {
+	dm.animCounter++
+
+	if dm.isBlinking {
+		dm.blinkCounter++
+		if dm.blinkCounter >= blinkDuration {
+			dm.isBlinking = false
+			dm.blinkCounter = 0
+			dm.ShowExpression(dm.currentExpr)
+		}
+		return
+	}
+
+	if dm.animCounter >= blinkInterval {
+		dm.animCounter = 0
+		savedExpr := dm.currentExpr
+		dm.isBlinking = true
+		dm.blinkCounter = 0
+		dm.device.ClearBuffer()
+		drawBlinkFace(dm.device, &dm.layout)
+		dm.device.Display()
+		dm.currentExpr = savedExpr
+	}
+}
@@ -0,0 +1,136 @@
+package pet
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestDisplayGeometry_PanelPixel(t *testing.T) {
+	tests := []struct {
+		name     string
+		rotation int
+		x, y     int16
+		wantX    int16
+		wantY    int16
+	}{
+		{"upright", ROTATE_0, 5, 3, 5, 3},
+		{"quarter turn, top left", ROTATE_90, 0, 0, 0, 31},
+		{"quarter turn, top right", ROTATE_90, 31, 0, 0, 0},
+		{"quarter turn, bottom left", ROTATE_90, 0, 127, 127, 31},
+		{"upside down", ROTATE_180, 0, 0, 127, 31},
+		{"upside down, bottom right", ROTATE_180, 127, 31, 0, 0},
+		{"three quarters, top left", ROTATE_270, 0, 0, 127, 0},
+		{"three quarters, top right", ROTATE_270, 31, 0, 127, 31},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := DisplayGeometry{Width: 128, Height: 32, Rotation: tt.rotation}
+			if x, y := g.PanelPixel(tt.x, tt.y); x != tt.wantX || y != tt.wantY {
+				t.Errorf("PanelPixel(%d, %d) = (%d, %d), want (%d, %d)", tt.x, tt.y, x, y, tt.wantX, tt.wantY)
+			}
+		})
+	}
+}
+
+func TestDisplayGeometry_FaceSize(t *testing.T) {
+	tests := []struct {
+		geometry DisplayGeometry
+		w, h     int16
+	}{
+		{DisplayGeometry{}, DEFAULT_DISPLAY_WIDTH, DEFAULT_DISPLAY_HEIGHT},
+		{DisplayGeometry{Width: 128, Height: 64}, 128, 64},
+		{DisplayGeometry{Width: 64, Height: 48, Rotation: ROTATE_90}, 48, 64},
+		{DisplayGeometry{Width: 128, Height: 32, Rotation: ROTATE_180}, 128, 32},
+		{DisplayGeometry{Width: 128, Height: 32, Rotation: ROTATE_270}, 32, 128},
+	}
+	for _, tt := range tests {
+		if w, h := tt.geometry.FaceSize(); w != tt.w || h != tt.h {
+			t.Errorf("%+v: FaceSize() = %dx%d, want %dx%d", tt.geometry, w, h, tt.w, tt.h)
+		}
+	}
+}
+
+// clipCounter counts pixels drawn outside its panel.
+type clipCounter struct {
+	*FakeRenderer
+	outside int
+}
+
+func (c *clipCounter) SetPixel(x, y int16, col color.RGBA) {
+	if x < 0 || y < 0 || x >= c.Geometry.Width || y >= c.Geometry.Height {
+		c.outside++
+	}
+	c.FakeRenderer.SetPixel(x, y, col)
+}
+
+func TestFaces_FitEveryPanel(t *testing.T) {
+	geometries := []DisplayGeometry{
+		{Width: 128, Height: 32},
+		{Width: 128, Height: 64},
+		{Width: 64, Height: 48},
+		{Width: 128, Height: 32, Rotation: ROTATE_90},
+		{Width: 128, Height: 64, Rotation: ROTATE_180},
+		{Width: 64, Height: 48, Rotation: ROTATE_270},
+	}
+	for _, g := range geometries {
+		panel := &clipCounter{FakeRenderer: NewFakeRenderer(g)}
+		dm := NewDisplayModule(panel, g)
+		for expr := EXPR_NEUTRAL; expr <= EXPR_LOW_BATTERY; expr++ {
+			dm.zzz = 3
+			dm.ShowExpression(expr)
+			if panel.outside != 0 {
+				t.Errorf("%dx%d rotation %d, expression %d: %d pixels outside the panel", g.Width, g.Height, g.Rotation, expr, panel.outside)
+				panel.outside = 0
+			}
+			if lit := litPixels(panel.FakeRenderer); lit == 0 {
+				t.Errorf("%dx%d rotation %d, expression %d: blank face", g.Width, g.Height, g.Rotation, expr)
+			}
+		}
+	}
+}
+
+func TestFaces_UpsideDownPanel(t *testing.T) {
+	upright := NewFakeRenderer(DisplayGeometry{Width: 128, Height: 64})
+	NewDisplayModule(upright, upright.Geometry).ShowExpression(EXPR_LOW_BATTERY)
+	flipped := NewFakeRenderer(DisplayGeometry{Width: 128, Height: 64, Rotation: ROTATE_180})
+	NewDisplayModule(flipped, flipped.Geometry).ShowExpression(EXPR_LOW_BATTERY)
+
+	for y := int16(0); y < 64; y++ {
+		for x := int16(0); x < 128; x++ {
+			if upright.Pixel(x, y) != flipped.Pixel(127-x, 63-y) {
+				t.Fatalf("pixel (%d, %d) differs from (%d, %d) on the upside-down panel", x, y, 127-x, 63-y)
+			}
+		}
+	}
+}
+
+func TestFaces_ScaleToPanel(t *testing.T) {
+	tests := []struct {
+		w, h   int16
+		eyeY   int16
+		radius int16
+	}{
+		{128, 32, 11, 4},
+		{128, 64, 22, 4},
+		{64, 48, 16, 2},
+		{32, 128, 44, 1},
+	}
+	for _, tt := range tests {
+		f := newFaceLayout(tt.w, tt.h)
+		if f.eyeY != tt.eyeY || f.px(4) != tt.radius {
+			t.Errorf("%dx%d: eye at y %d, radius %d; want %d, %d", tt.w, tt.h, f.eyeY, f.px(4), tt.eyeY, tt.radius)
+		}
+	}
+}
+
+func litPixels(r *FakeRenderer) int {
+	n := 0
+	for y := int16(0); y < fakeDisplayMaxHeight; y++ {
+		for x := int16(0); x < fakeDisplayMaxWidth; x++ {
+			if r.Pixel(x, y) {
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,242 @@
+package pet
+
+import (
+	"image/color"
+)
+
+var white = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+func setHLine(dev FaceRenderer, x, y, w int16) {
+	for i := int16(0); i < w; i++ {
+		dev.SetPixel(x+i, y, white)
+	}
+}
+
+func setFillRect(dev FaceRenderer, x, y, w, h int16) {
+	for dy := int16(0); dy < h; dy++ {
+		setHLine(dev, x, y+dy, w)
+	}
+}
+
+func setFillCircle(dev FaceRenderer, cx, cy, r int16) {
+	for dy := -r; dy <= r; dy++ {
+		for dx := -r; dx <= r; dx++ {
+			if dx*dx+dy*dy <= r*r {
+				dev.SetPixel(cx+dx, cy+dy, white)
+			}
+		}
+	}
+}
+
+func setCircle(dev FaceRenderer, cx, cy, r int16) {
+	x := r
+	y := int16(0)
+	p := 1 - r
+
+	for x >= y {
+		dev.SetPixel(cx+x, cy+y, white)
+		dev.SetPixel(cx-x, cy+y, white)
+		dev.SetPixel(cx+x, cy-y, white)
+		dev.SetPixel(cx-x, cy-y, white)
+		dev.SetPixel(cx+y, cy+x, white)
+		dev.SetPixel(cx-y, cy+x, white)
+		dev.SetPixel(cx+y, cy-x, white)
+		dev.SetPixel(cx-y, cy-x, white)
+		y++
+		if p <= 0 {
+			p += 2*y + 1
+		} else {
+			x--
+			p += 2*(y-x) + 1
+		}
+	}
+}
+
+// Faces are designed for a 128x32 panel and fitted to whichever panel is configured: features are placed in normalized
+// coordinates, faceUnits across the width and down the height of the panel, and their sizes, in design
+// pixels, are scaled by faceLayout.scale so that the whole design fits.
+const (
+	faceUnits    = 256
+	designWidth  = 128
+	designHeight = 32
+)
+
+// Feature positions in normalized coordinates.
+const (
+	eyeLeftX     = 80  // 40 of 128
+	eyeRightX    = 176 // 88 of 128
+	eyeY         = 88  // 11 of 32
+	mouthCX      = 128 // 64 of 128
+	mouthY       = 192 // 24 of 32
+	batteryIconX = 204 // 102 of 128
+	batteryIconY = 160 // 20 of 32
+	zzzX         = 200 // 100 of 128
+	zzzY         = 136 // 17 of 32
+)
+
+// faceLayout is the face fitted to one panel: feature positions in pixels and the size scale, faceUnits
+// for the design size.
+type faceLayout struct {
+	eyeLeftX, eyeRightX, eyeY int16
+	mouthCX, mouthY           int16
+	batteryX, batteryY        int16
+	zzzX, zzzY                int16
+	scale                     int32
+}
+
+func newFaceLayout(w, h int16) faceLayout {
+	return faceLayout{
+		eyeLeftX:  scaleUnits(eyeLeftX, w),
+		eyeRightX: scaleUnits(eyeRightX, w),
+		eyeY:      scaleUnits(eyeY, h),
+		mouthCX:   scaleUnits(mouthCX, w),
+		mouthY:    scaleUnits(mouthY, h),
+		batteryX:  scaleUnits(batteryIconX, w),
+		batteryY:  scaleUnits(batteryIconY, h),
+		zzzX:      scaleUnits(zzzX, w),
+		zzzY:      scaleUnits(zzzY, h),
+		scale:     min(int32(w)*faceUnits/designWidth, int32(h)*faceUnits/designHeight),
+	}
+}
+
+// scaleUnits converts a normalized coordinate to pixels on a side size pixels long.
+func scaleUnits(n int32, size int16) int16 {
+	return int16(n * int32(size) / faceUnits)
+}
+
+// px scales a length in design pixels to the panel, keeping at least one pixel of anything not zero.
+func (f *faceLayout) px(n int16) int16 {
+	v := int16(int32(n) * f.scale / faceUnits)
+	if v == 0 && n > 0 {
+		return 1
+	}
+	return v
+}
+
+// curve is how far the design parabola dx*dx/div has dropped at panel offset dx.
+func (f *faceLayout) curve(dx, div int16) int16 {
+	return int16(int32(dx) * int32(dx) * faceUnits / (int32(div) * f.scale))
+}
+
+func drawNeutralFace(dev FaceRenderer, f *faceLayout) {
+	setFillRect(dev, f.eyeLeftX-f.px(4), f.eyeY-f.px(1), f.px(8), f.px(2))
+	setFillRect(dev, f.eyeRightX-f.px(4), f.eyeY-f.px(1), f.px(8), f.px(2))
+	setFillRect(dev, f.mouthCX-f.px(5), f.mouthY, f.px(10), f.px(1))
+}
+
+func drawHappyFace(dev FaceRenderer, f *faceLayout) {
+	setFillCircle(dev, f.eyeLeftX, f.eyeY, f.px(4))
+	setFillCircle(dev, f.eyeRightX, f.eyeY, f.px(4))
+	for dx := -f.px(7); dx <= f.px(7); dx++ {
+		dev.SetPixel(f.mouthCX+dx, f.mouthY+f.curve(dx, 14), white)
+	}
+}
+
+func drawSurprisedFace(dev FaceRenderer, f *faceLayout) {
+	for _, cx := range [2]int16{f.eyeLeftX, f.eyeRightX} {
+		setCircle(dev, cx, f.eyeY, f.px(5))
+		setCircle(dev, cx, f.eyeY, f.px(4))
+	}
+	setCircle(dev, f.mouthCX, f.mouthY+f.px(1), f.px(3))
+	setCircle(dev, f.mouthCX, f.mouthY+f.px(1), f.px(2))
+}
+
+func drawScaredFace(dev FaceRenderer, f *faceLayout) {
+	for _, cx := range [2]int16{f.eyeLeftX, f.eyeRightX} {
+		setCircle(dev, cx, f.eyeY, f.px(5))
+		setCircle(dev, cx, f.eyeY, f.px(4))
+		setFillCircle(dev, cx, f.eyeY, f.px(1))
+	}
+	for dx := -f.px(7); dx <= f.px(7); dx++ {
+		dev.SetPixel(f.mouthCX+dx, f.mouthY+f.px(2)-f.curve(dx, 14), white)
+	}
+}
+
+func drawExcitedFace(dev FaceRenderer, f *faceLayout) {
+	for _, cx := range [2]int16{f.eyeLeftX, f.eyeRightX} {
+		setFillCircle(dev, cx, f.eyeY, f.px(4))
+		setFillRect(dev, cx-f.px(1), f.eyeY-f.px(6), f.px(2), f.px(3))
+		setFillRect(dev, cx-f.px(1), f.eyeY+f.px(4), f.px(2), f.px(3))
+		setFillRect(dev, cx-f.px(6), f.eyeY-f.px(1), f.px(3), f.px(2))
+		setFillRect(dev, cx+f.px(4), f.eyeY-f.px(1), f.px(3), f.px(2))
+	}
+	for dx := -f.px(9); dx <= f.px(9); dx++ {
+		dev.SetPixel(f.mouthCX+dx, f.mouthY+f.curve(dx, 20), white)
+	}
+}
+
+func drawBlinkFace(dev FaceRenderer, f *faceLayout) {
+	setHLine(dev, f.eyeLeftX-f.px(4), f.eyeY, f.px(8))
+	setHLine(dev, f.eyeRightX-f.px(4), f.eyeY, f.px(8))
+	setFillRect(dev, f.mouthCX-f.px(5), f.mouthY, f.px(10), f.px(1))
+}
+
+func drawSickFace(dev FaceRenderer, f *faceLayout) {
+	for _, cx := range [2]int16{f.eyeLeftX, f.eyeRightX} {
+		for d := -f.px(3); d <= f.px(3); d++ {
+			dev.SetPixel(cx+d, f.eyeY+d, white)
+			dev.SetPixel(cx+d, f.eyeY-d, white)
+		}
+	}
+	half, step := f.px(9), f.px(3)
+	for dx := -half; dx <= half; dx++ {
+		dy := int16(0)
+		if (dx+half)/step%2 == 1 {
+			dy = f.px(1)
+		}
+		dev.SetPixel(f.mouthCX+dx, f.mouthY+dy, white)
+	}
+}
+
+func drawSleepyFace(dev FaceRenderer, f *faceLayout) {
+	for _, cx := range [2]int16{f.eyeLeftX, f.eyeRightX} {
+		setHLine(dev, cx-f.px(5), f.eyeY-f.px(1), f.px(10))
+		setFillRect(dev, cx-f.px(3), f.eyeY, f.px(6), f.px(2))
+	}
+	setCircle(dev, f.mouthCX, f.mouthY+f.px(1), f.px(2))
+}
+
+func drawSleepingFace(dev FaceRenderer, f *faceLayout) {
+	for _, cx := range [2]int16{f.eyeLeftX, f.eyeRightX} {
+		for dx := -f.px(5); dx <= f.px(5); dx++ {
+			dev.SetPixel(cx+dx, f.eyeY+f.px(2)-f.curve(dx, 10), white)
+		}
+	}
+	setFillRect(dev, f.mouthCX-f.px(3), f.mouthY, f.px(6), f.px(1))
+}
+
+// drawLowBatteryFace draws drooping eyes and a nearly empty battery beside the right eye.
+func drawLowBatteryFace(dev FaceRenderer, f *faceLayout) {
+	for _, cx := range [2]int16{f.eyeLeftX, f.eyeRightX} {
+		setHLine(dev, cx-f.px(5), f.eyeY+f.px(1), f.px(10))
+		setFillRect(dev, cx-f.px(3), f.eyeY+f.px(2), f.px(6), f.px(1))
+	}
+	setHLine(dev, f.mouthCX-f.px(5), f.mouthY+f.px(1), f.px(10))
+
+	x, y, w, h := f.batteryX, f.batteryY, f.px(14), f.px(8)
+	setHLine(dev, x, y, w)
+	setHLine(dev, x, y+h-1, w)
+	for dy := int16(0); dy < h; dy++ {
+		dev.SetPixel(x, y+dy, white)
+		dev.SetPixel(x+w-1, y+dy, white)
+	}
+	setFillRect(dev, x+w, y+f.px(2), f.px(2), h-f.px(4))
+	setFillRect(dev, x+f.px(2), y+f.px(2), f.px(2), h-f.px(4))
+}
+
+// drawZzz draws n Zs, each larger and higher than the last, beside the right eye.
+func drawZzz(dev FaceRenderer, f *faceLayout, n int) {
+	// Offsets and sizes are in design pixels, scaled one by one so the Zs stay inside a small panel.
+	dx, dy, size := int16(0), int16(0), int16(3)
+	for i := 0; i < n; i++ {
+		x, y, s := f.zzzX+f.px(dx), f.zzzY-f.px(dy), f.px(size)
+		setHLine(dev, x, y, s)
+		for d := int16(1); d < s-1; d++ {
+			dev.SetPixel(x+s-1-d, y+d, white)
+		}
+		setHLine(dev, x, y+s-1, s)
+		dx += size + 3
+		dy += size + 2
+		size++
+	}
+}
@@ -0,0 +1,295 @@
+//go:build !tinygo
+
+package pet
+
+import (
+	"bytes"
+	"image/color"
+	"io"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+// FakeMotor records the last speed command.
+type FakeMotor struct {
+	Speed int
+}
+
+func (m *FakeMotor) SetSpeed(speed int) { m.Speed = speed }
+
+// FakeDistanceSensor returns Distance on every read, or a reading with Status when it is set to a fault.
+type FakeDistanceSensor struct {
+	Distance int
+	Status   int
+	Reads    int
+}
+
+func (s *FakeDistanceSensor) ReadRange() navlogic.RangeReading {
+	s.Reads++
+	if s.Status != navlogic.RangeOK {
+		return navlogic.RangeReading{Cm: navlogic.TimeoutDistance, Status: s.Status}
+	}
+	return navlogic.RangeFromCm(s.Distance)
+}
+
+// FakeEdgeSensor returns Value on every read.
+type FakeEdgeSensor struct {
+	Value uint16
+}
+
+func (s *FakeEdgeSensor) Get() uint16 {
+	return s.Value
+}
+
+// FakeIndicator tracks the output level and counts rising edges.
+type FakeIndicator struct {
+	On     bool
+	Pulses int
+}
+
+func (i *FakeIndicator) High() {
+	if !i.On {
+		i.Pulses++
+	}
+	i.On = true
+}
+
+func (i *FakeIndicator) Low() { i.On = false }
+
+// FakeBuzzer tracks the current pitch and counts tones started from silence.
+type FakeBuzzer struct {
+	Hz     uint16
+	Pulses int
+}
+
+func (b *FakeBuzzer) Tone(hz uint16) {
+	if b.Hz == 0 && hz != 0 {
+		b.Pulses++
+	}
+	b.Hz = hz
+}
+
+// FakeButton is a push button held down while Down is set.
+type FakeButton struct {
+	Down bool
+}
+
+func (b *FakeButton) Pressed() bool { return b.Down }
+
+// FakeBattery reads Mv as the pack voltage.
+type FakeBattery struct {
+	Mv uint16
+}
+
+func (b *FakeBattery) Millivolts() uint16 { return b.Mv }
+
+// FakeSerial is a serial port fed from In; everything written collects in Out.
+type FakeSerial struct {
+	In  []byte
+	Out bytes.Buffer
+}
+
+func (s *FakeSerial) Buffered() int { return len(s.In) }
+
+func (s *FakeSerial) ReadByte() (byte, error) {
+	if len(s.In) == 0 {
+		return 0, io.EOF
+	}
+	b := s.In[0]
+	s.In = s.In[1:]
+	return b, nil
+}
+
+func (s *FakeSerial) Write(p []byte) (int, error) { return s.Out.Write(p) }
+
+// Type queues line and a line ending as if typed into a terminal.
+func (s *FakeSerial) Type(line string) {
+	s.In = append(s.In, line+"\r\n"...)
+}
+
+// FakePower records idle waits without waiting. OnIdle, if set, runs after each wait, e.g. to press a
+// button meanwhile.
+type FakePower struct {
+	IdleMs uint32
+	Calls  int
+	OnIdle func()
+}
+
+func (p *FakePower) Idle(ms uint32) {
+	p.IdleMs += ms
+	p.Calls++
+	if p.OnIdle != nil {
+		p.OnIdle()
+	}
+}
+
+// FakeClock advances by Step on every read, so each Tick sees one main-loop period pass.
+type FakeClock struct {
+	Now  uint32
+	Step uint32
+}
+
+func (c *FakeClock) Millis() uint32 {
+	c.Now += c.Step
+	return c.Now
+}
+
+// FakeStorage is an in-memory EEPROM; a new one reads as erased (0xFF).
+type FakeStorage struct {
+	Data   [FAKE_STORAGE_SIZE]byte
+	Writes int
+}
+
+const FAKE_STORAGE_SIZE = 64
+
+func NewFakeStorage() *FakeStorage {
+	s := &FakeStorage{}
+	for i := range s.Data {
+		s.Data[i] = 0xFF
+	}
+	return s
+}
+
+func (s *FakeStorage) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 || off+int64(len(p)) > FAKE_STORAGE_SIZE {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return copy(p, s.Data[off:]), nil
+}
+
+func (s *FakeStorage) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 || off+int64(len(p)) > FAKE_STORAGE_SIZE {
+		return 0, io.ErrShortWrite
+	}
+	s.Writes++
+	return copy(s.Data[off:], p), nil
+}
+
+// FAKE_TICK_MS is the FakeClock step NewFakeRobot uses, so every Tick runs one navigation step.
+const FAKE_TICK_MS = NAV_PERIOD_MS
+
+// The largest panel a FakeRenderer can stand for.
+const (
+	fakeDisplayMaxWidth  = 128
+	fakeDisplayMaxHeight = 64
+)
+
+// FakeRenderer is an in-memory frame buffer for a panel of Geometry (zero: 128x32); Frames counts Display calls.
+type FakeRenderer struct {
+	Geometry DisplayGeometry
+	buffer   [fakeDisplayMaxHeight][fakeDisplayMaxWidth]bool
+	frame    [fakeDisplayMaxHeight][fakeDisplayMaxWidth]bool
+	Frames   int
+	Off      bool
+}
+
+// NewFakeRenderer returns a blank panel of the given geometry, at most 128x64.
+func NewFakeRenderer(geometry DisplayGeometry) *FakeRenderer {
+	return &FakeRenderer{Geometry: geometry}
+}
+
+func (r *FakeRenderer) ClearBuffer() {
+	r.buffer = [fakeDisplayMaxHeight][fakeDisplayMaxWidth]bool{}
+}
+
+func (r *FakeRenderer) inPanel(x, y int16) bool {
+	g := r.Geometry.withDefaults()
+	return x >= 0 && y >= 0 && x < min(g.Width, fakeDisplayMaxWidth) && y < min(g.Height, fakeDisplayMaxHeight)
+}
+
+func (r *FakeRenderer) SetPixel(x, y int16, c color.RGBA) {
+	if !r.inPanel(x, y) {
+		return
+	}
+	r.buffer[y][x] = c.R != 0 || c.G != 0 || c.B != 0
+}
+
+func (r *FakeRenderer) Display() error {
+	r.frame = r.buffer
+	r.Frames++
+	return nil
+}
+
+func (r *FakeRenderer) SetPower(on bool) {
+	r.Off = !on
+}
+
+// Pixel reports whether panel pixel (x, y) was lit in the last displayed frame.
+func (r *FakeRenderer) Pixel(x, y int16) bool {
+	if !r.inPanel(x, y) {
+		return false
+	}
+	return r.frame[y][x]
+}
+
+// FacePixel reports whether (x, y) of the face, as the viewer sees the mounted panel, was lit in the last
+// displayed frame.
+func (r *FakeRenderer) FacePixel(x, y int16) bool {
+	w, h := r.Geometry.FaceSize()
+	if x < 0 || y < 0 || x >= w || y >= h {
+		return false
+	}
+	return r.Pixel(r.Geometry.PanelPixel(x, y))
+}
+
+// FakeRobot bundles a Robot with the fakes behind it.
+type FakeRobot struct {
+	Robot      *Robot
+	LeftMotor  *FakeMotor
+	RightMotor *FakeMotor
+	Ultrasonic *FakeDistanceSensor
+	IRSensors  [IR_SENSOR_COUNT]*FakeEdgeSensor
+	StatusLed  *FakeIndicator
+	Buzzer     *FakeBuzzer
+	Display    *FakeRenderer
+	Clock      *FakeClock
+	Storage    *FakeStorage
+	Power      *FakePower
+	WakeButton *FakeButton
+	Battery    *FakeBattery
+	Serial     *FakeSerial
+}
+
+// FAKE_SURFACE_READING is the IR value a fake sensor reports over the table top.
+const FAKE_SURFACE_READING = 0xFFFF
+
+// FAKE_FULL_BATTERY_MV is the pack voltage of a fake robot: a fully charged 1S LiPo.
+const FAKE_FULL_BATTERY_MV = 4200
+
+// NewFakeRobot returns a Robot on an open table, with a full battery: no obstacle in range and every IR sensor over the surface.
+func NewFakeRobot() *FakeRobot {
+	fr := &FakeRobot{
+		LeftMotor:  &FakeMotor{},
+		RightMotor: &FakeMotor{},
+		Ultrasonic: &FakeDistanceSensor{Distance: navlogic.TimeoutDistance},
+		StatusLed:  &FakeIndicator{},
+		Buzzer:     &FakeBuzzer{},
+		Display:    &FakeRenderer{},
+		Clock:      &FakeClock{Step: FAKE_TICK_MS},
+		Storage:    NewFakeStorage(),
+		Power:      &FakePower{},
+		WakeButton: &FakeButton{},
+		Battery:    &FakeBattery{Mv: FAKE_FULL_BATTERY_MV},
+		Serial:     &FakeSerial{},
+	}
+	fr.Robot = &Robot{
+		LeftMotor:   fr.LeftMotor,
+		RightMotor:  fr.RightMotor,
+		Ultrasonic:  fr.Ultrasonic,
+		StatusLed:   fr.StatusLed,
+		Buzzer:      fr.Buzzer,
+		Display:     fr.Display,
+		Clock:       fr.Clock,
+		Storage:     fr.Storage,
+		Power:       fr.Power,
+		WakeButton:  fr.WakeButton,
+		Battery:     fr.Battery,
+		Serial:      fr.Serial,
+		BatteryPack: navlogic.PackLiPo1S,
+	}
+	for i := range fr.IRSensors {
+		fr.IRSensors[i] = &FakeEdgeSensor{Value: FAKE_SURFACE_READING}
+		fr.Robot.IRSensors[i] = fr.IRSensors[i]
+	}
+	return fr
+}
@@ -0,0 +1,66 @@
+package pet
+
+import "image/color"
+
+// Panel mountings: how far the OLED is turned clockwise from upright, where upright has the panel's
+// first row at the top, as the SSD1306 driver draws it.
+const (
+	ROTATE_0 = iota
+	ROTATE_90
+	ROTATE_180
+	ROTATE_270
+)
+
+// The panel a zero DisplayGeometry stands for.
+const (
+	DEFAULT_DISPLAY_WIDTH  = 128
+	DEFAULT_DISPLAY_HEIGHT = 32
+)
+
+// DisplayGeometry is the size of the OLED panel in its own pixels, as the SSD1306 driver is configured,
+// and how the panel is mounted. The zero value is an upright 128x32 panel.
+type DisplayGeometry struct {
+	Width, Height int16
+	Rotation      int // ROTATE_0 etc.
+}
+
+func (g DisplayGeometry) withDefaults() DisplayGeometry {
+	if g.Width <= 0 || g.Height <= 0 {
+		g.Width, g.Height = DEFAULT_DISPLAY_WIDTH, DEFAULT_DISPLAY_HEIGHT
+	}
+	return g
+}
+
+// FaceSize returns the width and height of the panel as the viewer sees it, which the face is fitted to.
+func (g DisplayGeometry) FaceSize() (w, h int16) {
+	g = g.withDefaults()
+	if g.Rotation == ROTATE_90 || g.Rotation == ROTATE_270 {
+		return g.Height, g.Width
+	}
+	return g.Width, g.Height
+}
+
+// PanelPixel maps a point of the face, as the viewer sees it, to the panel pixel that shows it.
+func (g DisplayGeometry) PanelPixel(x, y int16) (int16, int16) {
+	g = g.withDefaults()
+	switch g.Rotation {
+	case ROTATE_90:
+		return y, g.Height - 1 - x
+	case ROTATE_180:
+		return g.Width - 1 - x, g.Height - 1 - y
+	case ROTATE_270:
+		return g.Width - 1 - y, x
+	}
+	return x, y
+}
+
+// rotatedRenderer draws the face upright on a panel that is mounted turned.
+type rotatedRenderer struct {
+	FaceRenderer
+	geometry DisplayGeometry
+}
+
+func (r *rotatedRenderer) SetPixel(x, y int16, c color.RGBA) {
+	x, y = r.geometry.PanelPixel(x, y)
+	r.FaceRenderer.SetPixel(x, y, c)
+}
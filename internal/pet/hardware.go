@@ -0,0 +1,148 @@
+package pet
+
+import (
+	"image/color"
+	"time"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+// DistanceSensor measures the range to the nearest object ahead (HC-SR04 on hardware).
+type DistanceSensor interface {
+	// ReadRange returns the latest measurement and whether the sensor answered properly.
+	// It must not block for a whole ping; hardware returns the latest completed one.
+	ReadRange() navlogic.RangeReading
+}
+
+// EdgeSensor is an analog IR reflectance sensor; lower readings mean no surface below.
+type EdgeSensor interface {
+	Get() uint16
+}
+
+// EdgeSensorArray holds the IR edge sensors, indexed by IR_FRONT_LEFT etc.
+type EdgeSensorArray [IR_SENSOR_COUNT]EdgeSensor
+
+// MotorDriver is one side of the H-bridge.
+type MotorDriver interface {
+	// SetSpeed drives the motor at a signed speed: -100 full reverse, 0 stop, 100 full forward.
+	SetSpeed(speed int)
+}
+
+// FaceRenderer is the monochrome frame buffer faces are drawn into (SSD1306 on hardware).
+type FaceRenderer interface {
+	ClearBuffer()
+	SetPixel(x, y int16, c color.RGBA)
+	Display() error
+	// SetPower switches the panel on or off; the frame buffer is kept.
+	SetPower(on bool)
+}
+
+// Indicator is a digital output such as the status LED.
+type Indicator interface {
+	High()
+	Low()
+}
+
+// ToneGenerator drives the piezo buzzer with a square wave (a hardware timer on both boards).
+type ToneGenerator interface {
+	// Tone sounds hz until the next call; 0 silences the buzzer.
+	Tone(hz uint16)
+}
+
+// Button is a push button, such as the wake button.
+type Button interface {
+	Pressed() bool
+}
+
+// BatterySensor measures the pack voltage through a resistor divider on an ADC pin.
+type BatterySensor interface {
+	Millivolts() uint16
+}
+
+// SerialPort is the UART the command console runs on (machine.Serial on both boards).
+type SerialPort interface {
+	// Buffered returns how many received bytes are waiting, so reading never blocks.
+	Buffered() int
+	ReadByte() (byte, error)
+	Write(p []byte) (n int, err error)
+}
+
+// Storage is a small non-volatile byte store for the calibration record (EEPROM on AVR, a flash page on the Blue Pill).
+type Storage interface {
+	ReadAt(p []byte, off int64) (n int, err error)
+	WriteAt(p []byte, off int64) (n int, err error)
+}
+
+// Clock is the millisecond time base the main loop schedules motion against; it may wrap.
+type Clock interface {
+	Millis() uint32
+}
+
+// SystemClock counts milliseconds since it was created.
+type SystemClock struct {
+	start time.Time
+}
+
+func NewSystemClock() *SystemClock {
+	return &SystemClock{start: time.Now()}
+}
+
+func (c *SystemClock) Millis() uint32 {
+	return uint32(time.Since(c.start) / time.Millisecond)
+}
+
+// Power waits in a low-power mode between main-loop passes (idle sleep on AVR, WFI on the Blue Pill).
+type Power interface {
+	// Idle waits ms milliseconds with the CPU halted; interrupts keep the clock and the ultrasonic timing running.
+	Idle(ms uint32)
+}
+
+// SystemPower waits with time.Sleep.
+type SystemPower struct{}
+
+func (SystemPower) Idle(ms uint32) {
+	time.Sleep(time.Duration(ms) * time.Millisecond)
+}
+
+// Robot holds the drivers for the desk pet hardware.
+type Robot struct {
+	LeftMotor       MotorDriver
+	RightMotor      MotorDriver
+	Ultrasonic      DistanceSensor
+	IRSensors       EdgeSensorArray
+	StatusLed       Indicator
+	Buzzer          ToneGenerator
+	Display         FaceRenderer
+	DisplayGeometry DisplayGeometry // panel size and mounting; zero for an upright 128x32 panel
+	Clock           Clock
+	Storage         Storage
+	Power           Power
+	WakeButton      Button        // optional; nil when not fitted
+	Battery         BatterySensor // optional; nil when not fitted
+	Serial          SerialPort    // optional; nil without a console
+	BatteryPack     int           // navlogic.PackLiPo1S etc.
+}
+
+// BlinkLED blocks while it blinks, so it is only for startup and calibration; the main loop uses BehaviorPatterns.
+func (r *Robot) BlinkLED(times int) {
+	for i := 0; i < times; i++ {
+		r.StatusLed.High()
+		time.Sleep(time.Millisecond * 200)
+		r.StatusLed.Low()
+		time.Sleep(time.Millisecond * 200)
+	}
+}
+
+// BEEP_HZ is the pitch of plain beeps, near the resonance of common piezo buzzers.
+const BEEP_HZ = 2700
+
+func (r *Robot) Beep(duration time.Duration) {
+	r.Buzzer.Tone(BEEP_HZ)
+	time.Sleep(duration)
+	r.Buzzer.Tone(0)
+}
+
+func (r *Robot) Initialize() {
+	r.BlinkLED(3)
+	r.Beep(time.Millisecond * 500)
+}
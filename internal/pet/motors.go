@@ -0,0 +1,183 @@
+package pet
+
+import (
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+const (
+	MOVE_FORWARD = iota
+	MOVE_BACKWARD
+	TURN_LEFT
+	TURN_RIGHT
+	STOP
+	ARC
+)
+
+// The acceleration ramp changes a wheel speed by RAMP_STEP every RAMP_PERIOD_MS, whatever the Update period:
+// 0 to MAX_SPEED takes 250 ms.
+const (
+	MAX_SPEED      = navlogic.MaxSpeed
+	CREEP_SPEED    = navlogic.CreepSpeed
+	RAMP_STEP      = 10
+	RAMP_PERIOD_MS = 25
+)
+
+// MotorController drives both wheels with signed speeds (-MAX_SPEED..MAX_SPEED), ramping toward each new target.
+// Timed moves are scheduled as a navlogic.MotionPlan and advanced by Update, so they never block the main loop.
+type MotorController struct {
+	leftMotor        MotorDriver
+	rightMotor       MotorDriver
+	currentDirection int
+	speed            int
+	leftTarget       int
+	rightTarget      int
+	leftSpeed        int
+	rightSpeed       int
+	motion           navlogic.MotionPlan
+	rampMs           uint32
+	leftTrim         int
+	rightTrim        int
+}
+
+func NewMotorController(leftMotor, rightMotor MotorDriver) *MotorController {
+	return &MotorController{
+		leftMotor:        leftMotor,
+		rightMotor:       rightMotor,
+		currentDirection: STOP,
+		speed:            MAX_SPEED,
+	}
+}
+
+func (mc *MotorController) GetCurrentDirection() int {
+	return mc.currentDirection
+}
+
+// SetSpeed sets the wheel speed (0..MAX_SPEED) used by SetDirection; it applies from the next direction command.
+func (mc *MotorController) SetSpeed(speed int) {
+	if speed < 0 {
+		speed = 0
+	}
+	mc.speed = navlogic.ClampSpeed(speed)
+}
+
+func (mc *MotorController) GetSpeed() int {
+	return mc.speed
+}
+
+// SetTrim sets per-motor trims in percent (-navlogic.MaxTrim..0), applied to every speed sent to the motors
+// so a faster motor is slowed to match the other.
+func (mc *MotorController) SetTrim(left, right int) {
+	mc.leftTrim = max(min(left, 0), -navlogic.MaxTrim)
+	mc.rightTrim = max(min(right, 0), -navlogic.MaxTrim)
+}
+
+func (mc *MotorController) GetTrim() (left, right int) {
+	return mc.leftTrim, mc.rightTrim
+}
+
+// GetWheelSpeeds returns the commanded wheel speeds, before trim.
+func (mc *MotorController) GetWheelSpeeds() (left, right int) {
+	return mc.leftSpeed, mc.rightSpeed
+}
+
+// SetDirection drives in direction at the current speed, cancelling any timed move.
+func (mc *MotorController) SetDirection(direction int) {
+	mc.currentDirection = direction
+
+	switch direction {
+	case MOVE_FORWARD:
+		mc.SetWheelSpeeds(mc.speed, mc.speed)
+	case MOVE_BACKWARD:
+		mc.SetWheelSpeeds(-mc.speed, -mc.speed)
+	case TURN_LEFT:
+		mc.SetWheelSpeeds(-mc.speed, mc.speed)
+	case TURN_RIGHT:
+		mc.SetWheelSpeeds(mc.speed, -mc.speed)
+	case STOP:
+		mc.Stop()
+	}
+}
+
+// Drive mixes a signed linear speed and an angular rate (positive turns left) into wheel targets, cancelling any timed move.
+func (mc *MotorController) Drive(linear, angular int) {
+	mc.currentDirection = ARC
+	mc.SetWheelSpeeds(navlogic.MixDrive(linear, angular))
+}
+
+// SetWheelSpeeds sets signed per-wheel targets, cancelling any timed move; Update ramps the motors toward them.
+func (mc *MotorController) SetWheelSpeeds(left, right int) {
+	mc.motion.Cancel()
+	mc.setTargets(left, right)
+}
+
+func (mc *MotorController) setTargets(left, right int) {
+	mc.leftTarget = navlogic.ClampSpeed(left)
+	mc.rightTarget = navlogic.ClampSpeed(right)
+}
+
+// Stop cuts both motors at once; stopping is never ramped.
+func (mc *MotorController) Stop() {
+	mc.currentDirection = STOP
+	mc.motion.Cancel()
+	mc.leftTarget, mc.rightTarget = 0, 0
+	mc.leftSpeed, mc.rightSpeed = 0, 0
+	mc.leftMotor.SetSpeed(0)
+	mc.rightMotor.SetSpeed(0)
+}
+
+// Update advances any timed move by elapsedMs, then ramps the wheels toward their targets by RAMP_STEP for every
+// RAMP_PERIOD_MS elapsed. A finished timed move stops the motors.
+func (mc *MotorController) Update(elapsedMs uint32) {
+	if mc.motion.Active() {
+		step, running := mc.motion.Advance(elapsedMs)
+		if !running {
+			mc.Stop()
+			return
+		}
+		mc.setTargets(navlogic.MixDrive(step.Linear, step.Angular))
+	}
+	// Time short of a whole ramp period carries over to the next Update.
+	mc.rampMs += elapsedMs
+	step := int(mc.rampMs/RAMP_PERIOD_MS) * RAMP_STEP
+	mc.rampMs %= RAMP_PERIOD_MS
+	mc.leftSpeed = navlogic.RampToward(mc.leftSpeed, mc.leftTarget, step)
+	mc.rightSpeed = navlogic.RampToward(mc.rightSpeed, mc.rightTarget, step)
+	mc.leftMotor.SetSpeed(navlogic.ApplyTrim(mc.leftSpeed, mc.leftTrim))
+	mc.rightMotor.SetSpeed(navlogic.ApplyTrim(mc.rightSpeed, mc.rightTrim))
+}
+
+// StartMotion schedules a sequence of timed steps; Update runs them and stops when they finish.
+func (mc *MotorController) StartMotion(steps ...navlogic.MotionStep) {
+	mc.currentDirection = ARC
+	mc.motion.Start(steps...)
+}
+
+// MoveFor drives in direction at the current speed for durationMs.
+func (mc *MotorController) MoveFor(direction int, durationMs uint32) {
+	var linear, angular int
+	switch direction {
+	case MOVE_FORWARD:
+		linear = mc.speed
+	case MOVE_BACKWARD:
+		linear = -mc.speed
+	case TURN_LEFT:
+		angular = mc.speed
+	case TURN_RIGHT:
+		angular = -mc.speed
+	default:
+		mc.Stop()
+		return
+	}
+	mc.StartMotion(navlogic.MotionStep{Linear: linear, Angular: angular, DurationMs: durationMs})
+	mc.currentDirection = direction
+}
+
+// IsMoving reports whether a timed move is still running.
+func (mc *MotorController) IsMoving() bool {
+	return mc.motion.Active()
+}
+
+// CurrentMotion returns the running step of a timed move.
+func (mc *MotorController) CurrentMotion() (navlogic.MotionStep, bool) {
+	return mc.motion.Current()
+}
@@ -0,0 +1,163 @@
+package pet
+
+import "testing"
+
+func TestMotorController_RampsTowardTarget(t *testing.T) {
+	left, right := &FakeMotor{}, &FakeMotor{}
+	mc := NewMotorController(left, right)
+
+	mc.SetDirection(MOVE_FORWARD)
+	for _, want := range []int{40, 80, 100, 100} {
+		mc.Update(FAKE_TICK_MS)
+		if left.Speed != want || right.Speed != want {
+			t.Fatalf("motors = (%d, %d), want %d", left.Speed, right.Speed, want)
+		}
+	}
+
+	mc.SetDirection(TURN_LEFT)
+	mc.Update(FAKE_TICK_MS)
+	if left.Speed != 60 || right.Speed != 100 {
+		t.Errorf("turn left after one step = (%d, %d), want (60, 100)", left.Speed, right.Speed)
+	}
+}
+
+func TestMotorController_RampIndependentOfUpdatePeriod(t *testing.T) {
+	// The same time passed in short or long updates, or in updates shorter than RAMP_PERIOD_MS,
+	// reaches the same speed.
+	for _, periodMs := range []uint32{10, 15, 30, 75} {
+		left, right := &FakeMotor{}, &FakeMotor{}
+		mc := NewMotorController(left, right)
+		mc.SetDirection(MOVE_FORWARD)
+		for elapsed := uint32(0); elapsed < 150; elapsed += periodMs {
+			mc.Update(periodMs)
+		}
+		if left.Speed != 60 {
+			t.Errorf("%d ms updates: speed %d after 150 ms, want 60", periodMs, left.Speed)
+		}
+	}
+}
+
+func TestMotorController_StopIsImmediate(t *testing.T) {
+	left, right := &FakeMotor{}, &FakeMotor{}
+	mc := NewMotorController(left, right)
+	mc.SetDirection(MOVE_BACKWARD)
+	for i := 0; i < 5; i++ {
+		mc.Update(FAKE_TICK_MS)
+	}
+
+	mc.SetDirection(STOP)
+	if left.Speed != 0 || right.Speed != 0 {
+		t.Errorf("motors = (%d, %d) after STOP, want (0, 0)", left.Speed, right.Speed)
+	}
+	if l, r := mc.GetWheelSpeeds(); l != 0 || r != 0 {
+		t.Errorf("GetWheelSpeeds = (%d, %d), want (0, 0)", l, r)
+	}
+}
+
+func TestMotorController_SetSpeed(t *testing.T) {
+	tests := []struct {
+		name      string
+		speed     int
+		direction int
+		wantLeft  int
+		wantRight int
+	}{
+		{"creep forward", CREEP_SPEED, MOVE_FORWARD, CREEP_SPEED, CREEP_SPEED},
+		{"half backward", 50, MOVE_BACKWARD, -50, -50},
+		{"clamped high", 250, TURN_RIGHT, MAX_SPEED, -MAX_SPEED},
+		{"negative is zero", -30, MOVE_FORWARD, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left, right := &FakeMotor{}, &FakeMotor{}
+			mc := NewMotorController(left, right)
+			mc.SetSpeed(tt.speed)
+			mc.SetDirection(tt.direction)
+			for i := 0; i < 10; i++ {
+				mc.Update(FAKE_TICK_MS)
+			}
+			if left.Speed != tt.wantLeft || right.Speed != tt.wantRight {
+				t.Errorf("motors = (%d, %d), want (%d, %d)", left.Speed, right.Speed, tt.wantLeft, tt.wantRight)
+			}
+		})
+	}
+}
+
+func TestMotorController_TimedMoveRampsAndStops(t *testing.T) {
+	left, right := &FakeMotor{}, &FakeMotor{}
+	mc := NewMotorController(left, right)
+
+	mc.MoveFor(MOVE_FORWARD, 600)
+	peak, ticks := 0, 0
+	for mc.IsMoving() && ticks < 20 {
+		mc.Update(FAKE_TICK_MS)
+		peak = max(peak, left.Speed)
+		ticks++
+	}
+
+	if ticks != 7 {
+		t.Errorf("600 ms move took %d ticks of %d ms, want 7 (6 driving, 1 stopping)", ticks, FAKE_TICK_MS)
+	}
+	if peak != MAX_SPEED {
+		t.Errorf("peak speed = %d, want %d", peak, MAX_SPEED)
+	}
+	if left.Speed != 0 || right.Speed != 0 {
+		t.Errorf("motors = (%d, %d) after timed move, want stopped", left.Speed, right.Speed)
+	}
+}
+
+func TestMotorController_CommandCancelsTimedMove(t *testing.T) {
+	left, right := &FakeMotor{}, &FakeMotor{}
+	mc := NewMotorController(left, right)
+
+	mc.MoveFor(TURN_LEFT, 1000)
+	mc.Update(FAKE_TICK_MS)
+	mc.SetDirection(MOVE_FORWARD)
+	if mc.IsMoving() {
+		t.Fatal("timed move still running after SetDirection")
+	}
+	for i := 0; i < 10; i++ {
+		mc.Update(FAKE_TICK_MS)
+	}
+	if left.Speed != MAX_SPEED || right.Speed != MAX_SPEED {
+		t.Errorf("motors = (%d, %d), want forward at full speed", left.Speed, right.Speed)
+	}
+}
+
+func TestMotorController_DriveMixesArc(t *testing.T) {
+	left, right := &FakeMotor{}, &FakeMotor{}
+	mc := NewMotorController(left, right)
+
+	mc.Drive(60, 20)
+	for i := 0; i < 10; i++ {
+		mc.Update(FAKE_TICK_MS)
+	}
+	if left.Speed != 40 || right.Speed != 80 {
+		t.Errorf("motors = (%d, %d), want (40, 80) for a gentle left arc", left.Speed, right.Speed)
+	}
+	if got := mc.GetCurrentDirection(); got != ARC {
+		t.Errorf("direction = %d, want ARC", got)
+	}
+}
+
+func TestMotorController_TrimScalesOutput(t *testing.T) {
+	left, right := &FakeMotor{}, &FakeMotor{}
+	mc := NewMotorController(left, right)
+	mc.SetTrim(0, -8)
+
+	mc.SetDirection(MOVE_BACKWARD)
+	for i := 0; i < 10; i++ {
+		mc.Update(FAKE_TICK_MS)
+	}
+	if left.Speed != -MAX_SPEED || right.Speed != -92 {
+		t.Errorf("motors = (%d, %d), want (-100, -92) with right trim -8", left.Speed, right.Speed)
+	}
+	if l, r := mc.GetWheelSpeeds(); l != r {
+		t.Errorf("GetWheelSpeeds = (%d, %d), want the untrimmed command", l, r)
+	}
+
+	mc.SetTrim(5, -90)
+	if l, r := mc.GetTrim(); l != 0 || r != -30 {
+		t.Errorf("GetTrim = (%d, %d), want clamped to (0, -30)", l, r)
+	}
+}
@@ -0,0 +1,297 @@
+package pet
+
+import (
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+const (
+	IDLE_STATE               = navlogic.StateIdle
+	MOVING_STATE             = navlogic.StateMoving
+	OBSTACLE_AVOIDANCE_STATE = navlogic.StateObstacleAvoidance
+	EDGE_AVOIDANCE_STATE     = navlogic.StateEdgeAvoidance
+	INTERACTING_STATE        = navlogic.StateInteracting
+	GUARDING_STATE           = navlogic.StateGuarding
+	ALERT_STATE              = navlogic.StateAlert
+)
+
+const (
+	RANDOM_WALK_MODE = iota
+	GUARD_MODE
+	INTERACTIVE_MODE
+)
+
+const (
+	OBSTACLE_REVERSE_MS     = 600
+	OBSTACLE_TURN_MS        = 500
+	EDGE_REVERSE_MS         = 800
+	EDGE_TURN_MS            = 700
+	EDGE_HEAD_ON_REVERSE_MS = 1200
+	EDGE_TURN_AROUND_MS     = 1900
+	WIGGLE_MS               = 300
+)
+
+const (
+	AVOID_REVERSE_SPEED   = 80
+	AVOID_REVERSE_ANGULAR = 40
+	AVOID_TURN_SPEED      = 20
+	AVOID_TURN_ANGULAR    = 80
+	DEGRADED_SPEED        = CREEP_SPEED
+)
+
+// AvoidanceTiming holds the durations in ms of the reversing and turning arcs of each avoidance manoeuvre.
+type AvoidanceTiming struct {
+	ObstacleReverseMs uint32
+	ObstacleTurnMs    uint32
+	EdgeReverseMs     uint32
+	EdgeTurnMs        uint32
+	// EdgeHeadOnReverseMs and EdgeTurnAroundMs are the longer straight reverse and in-place 180° turn
+	// used when both front sensors see the edge.
+	EdgeHeadOnReverseMs uint32
+	EdgeTurnAroundMs    uint32
+}
+
+type NavigationModule struct {
+	motorController *MotorController
+	sensorModule    *SensorModule
+	currentState    int
+	behaviorMode    int
+	wanderAngular   int
+	turnLeft        bool
+	edgeTurn        int
+	loopCounter     uint8
+	avoidance       AvoidanceTiming
+	guardBaseline   navlogic.GuardBaseline
+	alertTicks      int
+	gestureHistory  navlogic.DistanceHistory
+	interactTicks   int
+	speedPercent    int
+}
+
+func NewNavigationModule(motorController *MotorController, sensorModule *SensorModule) *NavigationModule {
+	return &NavigationModule{
+		motorController: motorController,
+		sensorModule:    sensorModule,
+		currentState:    navlogic.StateIdle,
+		behaviorMode:    RANDOM_WALK_MODE,
+		speedPercent:    100,
+		avoidance: AvoidanceTiming{
+			ObstacleReverseMs:   OBSTACLE_REVERSE_MS,
+			ObstacleTurnMs:      OBSTACLE_TURN_MS,
+			EdgeReverseMs:       EDGE_REVERSE_MS,
+			EdgeTurnMs:          EDGE_TURN_MS,
+			EdgeHeadOnReverseMs: EDGE_HEAD_ON_REVERSE_MS,
+			EdgeTurnAroundMs:    EDGE_TURN_AROUND_MS,
+		},
+	}
+}
+
+func (nm *NavigationModule) SetAvoidanceTiming(timing AvoidanceTiming) {
+	nm.avoidance = timing
+}
+
+func (nm *NavigationModule) GetAvoidanceTiming() AvoidanceTiming {
+	return nm.avoidance
+}
+
+// SetBehaviorMode switches mode; a change stops the motors and restarts from idle (guard mode relearns its baseline).
+func (nm *NavigationModule) SetBehaviorMode(mode int) {
+	if mode == nm.behaviorMode {
+		return
+	}
+	nm.behaviorMode = mode
+	nm.motorController.SetDirection(STOP)
+	nm.currentState = navlogic.StateIdle
+	nm.guardBaseline = navlogic.GuardBaseline{}
+	nm.alertTicks = 0
+	nm.gestureHistory.Reset()
+}
+
+// SetSpeedPercent sets the share of the cruise speed above CREEP_SPEED the pet wanders at (see navlogic.ScaleCruiseSpeed).
+func (nm *NavigationModule) SetSpeedPercent(percent int) {
+	nm.speedPercent = percent
+}
+
+func (nm *NavigationModule) GetBehaviorMode() int {
+	return nm.behaviorMode
+}
+
+func (nm *NavigationModule) GetCurrentState() int {
+	return nm.currentState
+}
+
+func (nm *NavigationModule) ProcessState() {
+	nm.loopCounter++
+
+	if nm.behaviorMode == GUARD_MODE {
+		nm.processGuard()
+		return
+	}
+
+	raw := nm.sensorModule.ReadUltrasonicDistance()
+	distance := nm.sensorModule.FilterDistance(raw)
+	// With a faulty ultrasonic sensor the pet only trusts its edge sensors and creeps.
+	degraded := nm.sensorModule.IsSonarDegraded()
+	obstacleDetected := !degraded && nm.sensorModule.IsObstacle(distance)
+	ir := nm.sensorModule.ReadIRSensors()
+	edgeDetected := ir[IR_FRONT_LEFT] || ir[IR_FRONT_RIGHT]
+	rearEdge := ir[IR_REAR_LEFT] || ir[IR_REAR_RIGHT]
+	nm.edgeTurn = navlogic.DecideEdgeTurn(ir[IR_FRONT_LEFT], ir[IR_FRONT_RIGHT])
+
+	if nm.behaviorMode == INTERACTIVE_MODE && nm.currentState != navlogic.StateInteracting && !edgeDetected {
+		nm.gestureHistory.Push(raw)
+		if navlogic.IsPetGesture(nm.gestureHistory.Samples()) {
+			nm.gestureHistory.Reset()
+			nm.motorController.SetDirection(STOP)
+			nm.interactTicks = 0
+			nm.currentState = navlogic.StateInteracting
+			return
+		}
+	}
+
+	switch nm.currentState {
+	case navlogic.StateIdle:
+		nm.enterState(navlogic.NextStateFromSensors(nm.currentState, obstacleDetected, edgeDetected))
+
+	case navlogic.StateMoving:
+		nextState := navlogic.NextStateFromSensors(nm.currentState, obstacleDetected, edgeDetected)
+		if nextState != navlogic.StateMoving {
+			nm.enterState(nextState)
+			break
+		}
+		speed := navlogic.ScaleCruiseSpeed(navlogic.CruiseSpeed(distance, nm.sensorModule.obstacleThreshold, nm.sensorModule.IsNearEdge()), nm.speedPercent)
+		if degraded {
+			speed = DEGRADED_SPEED
+		}
+		nm.motorController.SetSpeed(speed)
+		if nm.behaviorMode == RANDOM_WALK_MODE && nm.loopCounter%navlogic.WanderPeriod == 0 {
+			nm.wanderAngular = navlogic.WanderAngular(nm.loopCounter)
+		}
+		nm.motorController.Drive(speed, nm.wanderAngular)
+
+	case navlogic.StateObstacleAvoidance, navlogic.StateEdgeAvoidance:
+		// The manoeuvre runs across ticks, so edges seen along the way change it at once:
+		// a rear edge cuts a reverse short and a front edge restarts the edge escape.
+		step, _ := nm.motorController.CurrentMotion()
+		switch navlogic.DecideEscape(edgeDetected, rearEdge, step.Linear) {
+		case navlogic.EscapeReverseArc:
+			nm.enterState(navlogic.StateEdgeAvoidance)
+		case navlogic.EscapeForwardArc:
+			nm.currentState = navlogic.StateEdgeAvoidance
+			nm.motorController.StartMotion(navlogic.MotionStep{Linear: AVOID_TURN_SPEED, Angular: nm.turnAngular(), DurationMs: nm.avoidance.EdgeTurnMs})
+		case navlogic.EscapeSpin:
+			nm.currentState = navlogic.StateEdgeAvoidance
+			nm.motorController.StartMotion(navlogic.MotionStep{Angular: nm.turnAngular(), DurationMs: nm.avoidance.EdgeTurnMs})
+		}
+		if !nm.motorController.IsMoving() {
+			nm.currentState = navlogic.StateMoving
+		}
+
+	case navlogic.StateInteracting:
+		if edgeDetected {
+			nm.enterState(navlogic.StateEdgeAvoidance)
+			break
+		}
+		nm.interactTicks++
+		if nm.interactTicks >= navlogic.InteractTicks {
+			nm.motorController.SetDirection(STOP)
+			nm.currentState = navlogic.StateMoving
+			break
+		}
+		if !nm.motorController.IsMoving() {
+			nm.wiggle()
+		}
+	}
+}
+
+// enterState switches to state, starting the avoidance arcs when it is an avoidance state.
+func (nm *NavigationModule) enterState(state int) {
+	nm.currentState = state
+	switch state {
+	case navlogic.StateObstacleAvoidance:
+		nm.turnLeft = nm.loopCounter%2 == 0
+		nm.startAvoidance(nm.avoidance.ObstacleReverseMs, nm.avoidance.ObstacleTurnMs)
+	case navlogic.StateEdgeAvoidance:
+		nm.startEdgeAvoidance()
+	}
+}
+
+// startEdgeAvoidance turns away from the side whose front sensor saw the edge; head-on it backs straight up further and turns around.
+func (nm *NavigationModule) startEdgeAvoidance() {
+	switch nm.edgeTurn {
+	case navlogic.EdgeTurnAround:
+		nm.turnLeft = nm.loopCounter%2 == 0
+		nm.motorController.SetDirection(STOP)
+		nm.motorController.StartMotion(
+			navlogic.MotionStep{Linear: -AVOID_REVERSE_SPEED, DurationMs: nm.avoidance.EdgeHeadOnReverseMs},
+			navlogic.MotionStep{Angular: nm.turnAngular(), DurationMs: nm.avoidance.EdgeTurnAroundMs},
+		)
+		nm.wanderAngular = 0
+		return
+	case navlogic.EdgeTurnLeft:
+		nm.turnLeft = true
+	case navlogic.EdgeTurnRight:
+		nm.turnLeft = false
+	default:
+		nm.turnLeft = nm.loopCounter%2 == 0
+	}
+	nm.startAvoidance(nm.avoidance.EdgeReverseMs, nm.avoidance.EdgeTurnMs)
+}
+
+// startAvoidance reverses along a curve that swings the nose away, then arcs forward the same way to finish the turn.
+func (nm *NavigationModule) startAvoidance(reverseMs, turnMs uint32) {
+	nm.motorController.SetDirection(STOP)
+	angular := AVOID_REVERSE_ANGULAR
+	if !nm.turnLeft {
+		angular = -angular
+	}
+	nm.motorController.StartMotion(
+		navlogic.MotionStep{Linear: -AVOID_REVERSE_SPEED, Angular: angular, DurationMs: reverseMs},
+		navlogic.MotionStep{Linear: AVOID_TURN_SPEED, Angular: nm.turnAngular(), DurationMs: turnMs},
+	)
+	nm.wanderAngular = 0
+}
+
+// turnAngular is the turning rate of the current avoidance, keeping every arc of one escape turning the same way.
+func (nm *NavigationModule) turnAngular() int {
+	if nm.turnLeft {
+		return AVOID_TURN_ANGULAR
+	}
+	return -AVOID_TURN_ANGULAR
+}
+
+// wiggle twists the pet left then right in place.
+func (nm *NavigationModule) wiggle() {
+	nm.motorController.StartMotion(
+		navlogic.MotionStep{Angular: MAX_SPEED, DurationMs: WIGGLE_MS},
+		navlogic.MotionStep{Angular: -MAX_SPEED, DurationMs: WIGGLE_MS},
+	)
+}
+
+// processGuard keeps the pet parked and watches the ultrasonic range for something approaching.
+func (nm *NavigationModule) processGuard() {
+	nm.motorController.SetDirection(STOP)
+
+	distance := nm.sensorModule.ReadUltrasonicDistance()
+	intrusion := navlogic.IsGuardIntrusion(nm.guardBaseline, distance)
+	if nm.currentState == navlogic.StateAlert {
+		nm.alertTicks++
+	}
+
+	nextState := navlogic.NextGuardState(nm.currentState, intrusion, nm.alertTicks)
+	if nextState != nm.currentState {
+		nm.alertTicks = 0
+	}
+	nm.currentState = nextState
+	if nextState == navlogic.StateGuarding && !intrusion {
+		nm.guardBaseline = navlogic.LearnGuardBaseline(nm.guardBaseline, distance)
+	}
+}
+
+func (nm *NavigationModule) Update() {
+	nm.ProcessState()
+}
+
+func (nm *NavigationModule) EmergencyStop() {
+	nm.motorController.SetDirection(STOP)
+	nm.currentState = navlogic.StateIdle
+}
@@ -0,0 +1,226 @@
+// Package pet implements the desk pet modules on top of small hardware interfaces, so the same code runs on a board or on the host.
+package pet
+
+import (
+	"github.com/GyeongHoKim/tiny-pet/internal/console"
+	"github.com/GyeongHoKim/tiny-pet/internal/logging"
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+// Pet wires the modules to a Robot; main drives it on hardware, tests drive it with fakes.
+type Pet struct {
+	robot       *Robot
+	sensors     *SensorModule
+	motors      *MotorController
+	navigation  *NavigationModule
+	behaviors   *BehaviorPatterns
+	calibration *CalibrationModule
+	display     *DisplayModule
+	lastState   int
+	lastTickMs  uint32
+	navElapsed  uint32
+	degraded    bool
+	mood        navlogic.Mood
+	feeling     int
+
+	asleep       bool
+	sleepTimer   navlogic.SleepTimer
+	sleepCycle   navlogic.SleepCycle
+	sleepCheckMs uint32
+	wakePressed  bool
+	screenOn     bool
+	zzz          int
+
+	battery        navlogic.BatteryMonitor
+	batteryLevel   int
+	batteryCheckMs uint32
+	batteryWarnMs  uint32
+
+	lines  console.LineBuffer
+	out    *console.Printer
+	manual bool // console drive commands have taken over from navigation
+
+	telemetryOn  bool
+	telemetrySeq uint16
+	frame        [navlogic.TelemetryFrameSize]byte
+}
+
+// NAV_PERIOD_MS is how often Tick runs navigation; the main loop calls Tick more often so LED and buzzer
+// patterns stay smooth.
+const NAV_PERIOD_MS = 100
+
+func New(robot *Robot) *Pet {
+	sensorModule := NewSensorModule(robot.Ultrasonic, &robot.IRSensors)
+	motorController := NewMotorController(robot.LeftMotor, robot.RightMotor)
+	return &Pet{
+		robot:       robot,
+		sensors:     sensorModule,
+		motors:      motorController,
+		navigation:  NewNavigationModule(motorController, sensorModule),
+		behaviors:   NewBehaviorPatterns(robot.StatusLed, robot.Buzzer),
+		calibration: NewCalibrationModule(robot, sensorModule, motorController),
+		display:     NewDisplayModule(robot.Display, robot.DisplayGeometry),
+		lastState:   -1,
+		lastTickMs:  robot.Clock.Millis(),
+		mood:        navlogic.NewMood(),
+		feeling:     navlogic.FeelingContent,
+		sleepTimer:  navlogic.SleepTimer{AfterMs: navlogic.SleepAfterMs},
+		battery:     navlogic.BatteryMonitor{Pack: robot.BatteryPack},
+		// The first Tick reads the battery straight away.
+		batteryLevel:   navlogic.BatteryAbsent,
+		batteryCheckMs: BATTERY_CHECK_MS,
+		out:            console.NewPrinter(robot.Serial),
+	}
+}
+
+// Start runs the power-on sequence: hardware self-test, saved calibration (or a fresh one), then random walk.
+// Holding a hand within RECALIBRATE_HAND_CM of the ultrasonic sensor at power-on forces recalibration.
+func (p *Pet) Start() {
+	p.robot.Initialize()
+	if navlogic.IsWithinThreshold(p.sensors.ReadUltrasonicDistance(), RECALIBRATE_HAND_CM) {
+		logging.Info(logging.Calib, "Recalibration requested")
+		p.calibration.Recalibrate()
+	} else if err := p.calibration.LoadCalibration(); err != nil {
+		logging.Err(logging.LevelInfo, logging.Calib, "No saved calibration", err)
+		p.calibration.Recalibrate()
+	}
+	p.navigation.SetBehaviorMode(RANDOM_WALK_MODE)
+	p.display.ShowExpression(EXPR_HAPPY)
+	p.lastTickMs = p.robot.Clock.Millis()
+}
+
+// Tick runs one main-loop iteration: the battery check, a navigation step once NAV_PERIOD_MS has passed
+// (or the sleep checks while asleep, or nothing while the battery is flat), console commands, the
+// indication patterns, then a telemetry frame when telemetry is on. Timed moves and patterns advance by
+// the clock time since they last ran.
+func (p *Pet) Tick() {
+	now := p.robot.Clock.Millis()
+	elapsed := now - p.lastTickMs
+	p.lastTickMs = now
+
+	p.checkBattery(elapsed)
+	switch {
+	case p.isBatteryFlat():
+	case p.asleep:
+		p.dream(elapsed)
+	default:
+		p.navElapsed += elapsed
+		if p.navElapsed >= NAV_PERIOD_MS {
+			p.step(p.navElapsed)
+			p.navElapsed = 0
+		}
+	}
+	p.serviceConsole()
+	p.behaviors.Update(elapsed)
+	if p.telemetryOn {
+		p.sendTelemetry(now, elapsed)
+	}
+}
+
+// step runs navigation and reacts to state, mood and sensor health changes.
+func (p *Pet) step(elapsed uint32) {
+	if p.manual {
+		p.driveManually(elapsed)
+		p.display.UpdateAnimation()
+		return
+	}
+	p.navigation.Update()
+	p.motors.Update(elapsed)
+
+	degraded := p.sensors.IsSonarDegraded()
+	degradedChanged := degraded != p.degraded
+	if degradedChanged {
+		p.degraded = degraded
+		if degraded {
+			logging.Warn(logging.Sensors, "Ultrasonic fault: edge-only navigation")
+			p.behaviors.SoundErrorCode(ERROR_CODE_SONAR)
+		}
+	}
+
+	currentState := p.navigation.GetCurrentState()
+	stateChanged := currentState != p.lastState
+	if stateChanged {
+		logging.Trace(logging.Nav, "State", currentState)
+		p.behaviors.IndicateStateChange(currentState)
+		if event, ok := moodEvent(currentState); ok {
+			p.mood.React(event)
+		}
+		p.lastState = currentState
+	}
+
+	resting := currentState == IDLE_STATE || currentState == GUARDING_STATE || currentState == ALERT_STATE
+	p.mood.Advance(elapsed, !resting)
+	feeling := p.mood.Feeling()
+	feelingChanged := feeling != p.feeling
+	if feelingChanged {
+		p.feeling = feeling
+		p.navigation.SetSpeedPercent(p.speedPercent())
+		// A state change already made its own sound.
+		if !stateChanged {
+			p.behaviors.IndicateFeeling(feeling)
+		}
+	}
+	if stateChanged || degradedChanged || feelingChanged {
+		p.showFace(currentState)
+	}
+
+	// The pet only nods off while cruising, never halfway through a manoeuvre.
+	if p.sleepTimer.Advance(elapsed, currentState == MOVING_STATE) && currentState == MOVING_STATE {
+		p.fallAsleep()
+		return
+	}
+	p.display.UpdateAnimation()
+}
+
+// showFace shows the expression for state and mood, the sick face while a faulty ultrasonic sensor keeps
+// the pet degraded, or the low-battery face while the pet is calm on a low battery.
+func (p *Pet) showFace(state int) {
+	switch {
+	case p.degraded && state != EDGE_AVOIDANCE_STATE:
+		p.display.ShowExpression(EXPR_SICK)
+	case p.batteryLevel == navlogic.BatteryLow && isCalmState(state):
+		p.display.ShowExpression(EXPR_LOW_BATTERY)
+	default:
+		p.display.ShowMoodExpression(state, p.feeling)
+	}
+}
+
+// moodEvent returns the mood event of entering state, if it has one.
+func moodEvent(state int) (int, bool) {
+	switch state {
+	case OBSTACLE_AVOIDANCE_STATE:
+		return navlogic.MoodEventObstacle, true
+	case EDGE_AVOIDANCE_STATE:
+		return navlogic.MoodEventEdgeScare, true
+	case INTERACTING_STATE:
+		return navlogic.MoodEventInteraction, true
+	case ALERT_STATE:
+		return navlogic.MoodEventIntruder, true
+	}
+	return 0, false
+}
+
+// Mood returns the pet's current emotions.
+func (p *Pet) Mood() navlogic.Mood {
+	return p.mood
+}
+
+func (p *Pet) Sensors() *SensorModule {
+	return p.sensors
+}
+
+func (p *Pet) Motors() *MotorController {
+	return p.motors
+}
+
+func (p *Pet) Navigation() *NavigationModule {
+	return p.navigation
+}
+
+func (p *Pet) Calibration() *CalibrationModule {
+	return p.calibration
+}
+
+func (p *Pet) Display() *DisplayModule {
+	return p.display
+}
@@ -0,0 +1,479 @@
+package pet
+
+import (
+	"testing"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+// soundedNotes counts the notes of melody that are not rests.
+func soundedNotes(melody string) int {
+	n := 0
+	for pos := 0; ; {
+		note, next, ok := navlogic.NextNote(melody, pos)
+		if !ok {
+			return n
+		}
+		if note.Hz != 0 {
+			n++
+		}
+		pos = next
+	}
+}
+
+// runFor ticks p through ms of clock time in main-loop sized steps, so LED and buzzer patterns play out.
+func runFor(p *Pet, fr *FakeRobot, ms uint32) {
+	const stepMs = 10
+	fr.Clock.Step = stepMs
+	for t := uint32(0); t < ms; t += stepMs {
+		p.Tick()
+	}
+	fr.Clock.Step = FAKE_TICK_MS
+}
+
+func TestTick_WandersForwardOnOpenTable(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+
+	p.Tick()
+	p.Tick()
+
+	if got := p.navigation.GetCurrentState(); got != MOVING_STATE {
+		t.Fatalf("state = %d, want MOVING_STATE", got)
+	}
+	if fr.LeftMotor.Speed <= 0 || fr.RightMotor.Speed <= 0 {
+		t.Errorf("motors = (%d, %d), want both forward", fr.LeftMotor.Speed, fr.RightMotor.Speed)
+	}
+	if p.display.currentExpr != EXPR_HAPPY {
+		t.Errorf("expression = %d, want EXPR_HAPPY", p.display.currentExpr)
+	}
+	if fr.Display.Frames == 0 {
+		t.Error("display never refreshed")
+	}
+}
+
+func TestTick_SlowsAsObstacleApproaches(t *testing.T) {
+	tests := []struct {
+		name     string
+		distance int
+		irValue  uint16
+		want     int
+	}{
+		{"open table", 200, FAKE_SURFACE_READING, MAX_SPEED},
+		{"obstacle in slowdown range", 40, FAKE_SURFACE_READING, navlogic.CruiseSpeed(40, OBSTACLE_DISTANCE_THRESHOLD, false)},
+		{"near an edge", 200, EDGE_DETECTION_THRESHOLD + 1, CREEP_SPEED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fr := NewFakeRobot()
+			p := New(fr.Robot)
+			fr.Ultrasonic.Distance = tt.distance
+			fr.IRSensors[IR_FRONT_RIGHT].Value = tt.irValue
+			for i := 0; i < 10; i++ {
+				p.Tick()
+			}
+			if fr.LeftMotor.Speed != tt.want || fr.RightMotor.Speed != tt.want {
+				t.Errorf("motors = (%d, %d), want %d", fr.LeftMotor.Speed, fr.RightMotor.Speed, tt.want)
+			}
+		})
+	}
+}
+
+func TestTick_RandomWalkCurves(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+
+	p.Tick()
+	curved := false
+	for i := 0; i < 8*navlogic.WanderPeriod; i++ {
+		p.Tick()
+		if fr.LeftMotor.Speed <= 0 || fr.RightMotor.Speed <= 0 {
+			t.Fatalf("tick %d: motors = (%d, %d), want both wheels forward", i, fr.LeftMotor.Speed, fr.RightMotor.Speed)
+		}
+		if fr.LeftMotor.Speed != fr.RightMotor.Speed {
+			curved = true
+		}
+	}
+	if !curved {
+		t.Error("random walk never curved")
+	}
+}
+
+func TestTick_StateChangeIndicatesAndShowsFace(t *testing.T) {
+	tests := []struct {
+		name     string
+		distance int
+		irValue  uint16
+		want     int
+		wantExpr int
+	}{
+		{"obstacle", OBSTACLE_DISTANCE_THRESHOLD - 5, FAKE_SURFACE_READING, OBSTACLE_AVOIDANCE_STATE, EXPR_SURPRISED},
+		{"edge", 100, EDGE_DETECTION_THRESHOLD - 1, EDGE_AVOIDANCE_STATE, EXPR_SCARED},
+		{"edge beats obstacle", 5, 0, EDGE_AVOIDANCE_STATE, EXPR_SCARED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fr := NewFakeRobot()
+			p := New(fr.Robot)
+			p.Tick()
+
+			fr.Ultrasonic.Distance = tt.distance
+			fr.IRSensors[IR_FRONT_LEFT].Value = tt.irValue
+			p.Tick()
+			p.Tick()
+
+			if got := p.navigation.GetCurrentState(); got != tt.want {
+				t.Errorf("state = %d, want %d", got, tt.want)
+			}
+			if p.display.currentExpr != tt.wantExpr {
+				t.Errorf("expression = %d, want %d", p.display.currentExpr, tt.wantExpr)
+			}
+		})
+	}
+}
+
+func TestTick_AvoidanceArcsAcrossTicksThenResumes(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	p.Tick()
+
+	fr.Ultrasonic.Distance = 5
+	p.Tick()
+	p.Tick()
+	fr.Ultrasonic.Distance = 100
+	p.Tick()
+	if got := p.navigation.GetCurrentState(); got != OBSTACLE_AVOIDANCE_STATE {
+		t.Fatalf("state = %d, want OBSTACLE_AVOIDANCE_STATE while the manoeuvre runs", got)
+	}
+	if fr.LeftMotor.Speed >= 0 || fr.RightMotor.Speed >= 0 {
+		t.Errorf("motors = (%d, %d), want reversing", fr.LeftMotor.Speed, fr.RightMotor.Speed)
+	}
+
+	manoeuvreTicks := int((OBSTACLE_REVERSE_MS + OBSTACLE_TURN_MS) / FAKE_TICK_MS)
+	for i := 0; i < manoeuvreTicks+1; i++ {
+		p.Tick()
+	}
+	if got := p.navigation.GetCurrentState(); got != MOVING_STATE {
+		t.Errorf("state = %d, want MOVING_STATE", got)
+	}
+}
+
+func TestTick_SpuriousEchoIgnored(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	p.Tick()
+
+	fr.Ultrasonic.Distance = 5
+	p.Tick()
+	fr.Ultrasonic.Distance = 100
+	p.Tick()
+	if got := p.navigation.GetCurrentState(); got != MOVING_STATE {
+		t.Errorf("state = %d after a single close echo, want MOVING_STATE", got)
+	}
+}
+
+func TestTick_SonarFaultDegradesToEdgeOnly(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	fr.Ultrasonic.Status = navlogic.RangeNoEcho
+	for i := 0; i < navlogic.SonarFaultLimit-1; i++ {
+		p.Tick()
+	}
+	if p.sensors.IsSonarDegraded() {
+		t.Fatal("degraded before SonarFaultLimit faults")
+	}
+
+	beeps := fr.Buzzer.Pulses
+	p.Tick()
+	runFor(p, fr, ERROR_CODE_SONAR*(ERROR_BEEP_MS+ERROR_GAP_MS))
+	if got := fr.Buzzer.Pulses - beeps; got != ERROR_CODE_SONAR {
+		t.Errorf("error beeps = %d, want %d", got, ERROR_CODE_SONAR)
+	}
+	if got := p.display.GetCurrentExpression(); got != EXPR_SICK {
+		t.Errorf("expression = %d, want EXPR_SICK", got)
+	}
+	for i := 0; i < 10; i++ {
+		p.Tick()
+	}
+	if got := p.navigation.GetCurrentState(); got != MOVING_STATE {
+		t.Errorf("state = %d, want MOVING_STATE", got)
+	}
+	if l, r := p.motors.GetWheelSpeeds(); max(l, r) > DEGRADED_SPEED {
+		t.Errorf("wheel speeds = (%d, %d), want at most DEGRADED_SPEED", l, r)
+	}
+
+	fr.IRSensors[IR_FRONT_LEFT].Value = 0
+	p.Tick()
+	if got := p.navigation.GetCurrentState(); got != EDGE_AVOIDANCE_STATE {
+		t.Errorf("state = %d, want EDGE_AVOIDANCE_STATE: edges still work when degraded", got)
+	}
+	fr.IRSensors[IR_FRONT_LEFT].Value = FAKE_SURFACE_READING
+
+	fr.Ultrasonic.Status = navlogic.RangeOK
+	fr.Ultrasonic.Distance = 100
+	for i := 0; i < navlogic.SonarRecoverReadings+int((EDGE_REVERSE_MS+EDGE_TURN_MS)/FAKE_TICK_MS)+1; i++ {
+		p.Tick()
+	}
+	if p.sensors.IsSonarDegraded() {
+		t.Error("still degraded after the sensor recovered")
+	}
+	if got := p.display.GetCurrentExpression(); got != EXPR_HAPPY {
+		t.Errorf("expression = %d after recovery, want EXPR_HAPPY", got)
+	}
+}
+
+func TestTick_EdgesDuringAvoidance(t *testing.T) {
+	reverseTicks := int(OBSTACLE_REVERSE_MS / FAKE_TICK_MS)
+	tests := []struct {
+		name        string
+		ticksBefore int
+		sensor      int
+		wantLinear  int
+	}{
+		{"rear edge cuts the reverse short", 0, IR_REAR_LEFT, AVOID_TURN_SPEED},
+		{"front edge while reversing keeps reversing", 0, IR_FRONT_LEFT, -AVOID_REVERSE_SPEED},
+		{"front edge while turning restarts the edge escape", reverseTicks, IR_FRONT_LEFT, -AVOID_REVERSE_SPEED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fr := NewFakeRobot()
+			p := New(fr.Robot)
+			p.Tick()
+			fr.Ultrasonic.Distance = 5
+			p.Tick()
+			p.Tick()
+			fr.Ultrasonic.Distance = 100
+			for i := 0; i < tt.ticksBefore+1; i++ {
+				p.Tick()
+			}
+
+			fr.IRSensors[tt.sensor].Value = 0
+			p.Tick()
+			step, ok := p.motors.CurrentMotion()
+			if !ok || step.Linear != tt.wantLinear {
+				t.Errorf("motion = (%+v, %v), want linear %d", step, ok, tt.wantLinear)
+			}
+		})
+	}
+}
+
+func TestTick_EdgeAvoidanceTurnsAwayFromSensor(t *testing.T) {
+	tests := []struct {
+		name          string
+		sensors       []int
+		wantReverseMs uint32
+		wantTurnLeft  bool
+		wantSpin      bool
+	}{
+		{"left edge turns right", []int{IR_FRONT_LEFT}, EDGE_REVERSE_MS, false, false},
+		{"right edge turns left", []int{IR_FRONT_RIGHT}, EDGE_REVERSE_MS, true, false},
+		{"head-on backs up further and turns around", []int{IR_FRONT_LEFT, IR_FRONT_RIGHT}, EDGE_HEAD_ON_REVERSE_MS, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, odd := range []bool{false, true} {
+				fr := NewFakeRobot()
+				p := New(fr.Robot)
+				p.Tick()
+				if odd {
+					p.Tick()
+				}
+				for _, i := range tt.sensors {
+					fr.IRSensors[i].Value = 0
+				}
+				p.Tick()
+
+				reverse, ok := p.motors.CurrentMotion()
+				if !ok || reverse.Linear >= 0 || reverse.DurationMs != tt.wantReverseMs {
+					t.Fatalf("first step = (%+v, %v), want a %d ms reverse", reverse, ok, tt.wantReverseMs)
+				}
+				for i := 0; i < int(tt.wantReverseMs/FAKE_TICK_MS); i++ {
+					p.Tick()
+				}
+				turn, ok := p.motors.CurrentMotion()
+				if !ok {
+					t.Fatal("manoeuvre ended after the reverse")
+				}
+				if tt.wantSpin {
+					if turn.Linear != 0 || turn.DurationMs != EDGE_TURN_AROUND_MS {
+						t.Errorf("turn step = %+v, want a %d ms turn in place", turn, EDGE_TURN_AROUND_MS)
+					}
+					continue
+				}
+				if (turn.Angular > 0) != tt.wantTurnLeft || (reverse.Angular > 0) != tt.wantTurnLeft {
+					t.Errorf("reverse angular %d, turn angular %d; want turning left = %v", reverse.Angular, turn.Angular, tt.wantTurnLeft)
+				}
+			}
+		})
+	}
+}
+
+func TestTick_EdgesFrontAndRearSpinInPlace(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	p.Tick()
+	fr.IRSensors[IR_FRONT_RIGHT].Value = 0
+	p.Tick()
+	fr.IRSensors[IR_REAR_RIGHT].Value = 0
+	p.Tick()
+
+	if got := p.navigation.GetCurrentState(); got != EDGE_AVOIDANCE_STATE {
+		t.Fatalf("state = %d, want EDGE_AVOIDANCE_STATE", got)
+	}
+	if step, ok := p.motors.CurrentMotion(); !ok || step.Linear != 0 || step.Angular == 0 {
+		t.Errorf("motion = (%+v, %v), want a turn in place", step, ok)
+	}
+}
+
+func TestTick_RearSensorsAreOptional(t *testing.T) {
+	fr := NewFakeRobot()
+	fr.Robot.IRSensors[IR_REAR_LEFT] = nil
+	fr.Robot.IRSensors[IR_REAR_RIGHT] = nil
+	p := New(fr.Robot)
+	p.Tick()
+	fr.Ultrasonic.Distance = 5
+	p.Tick()
+	p.Tick()
+
+	if step, ok := p.motors.CurrentMotion(); !ok || step.Linear >= 0 {
+		t.Errorf("motion = (%+v, %v), want reversing without rear sensors", step, ok)
+	}
+}
+
+func TestTick_GuardModeAlertsOnApproach(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	p.Navigation().SetBehaviorMode(GUARD_MODE)
+
+	fr.Ultrasonic.Distance = 120
+	for i := 0; i < navlogic.GuardLearnSamples+1; i++ {
+		p.Tick()
+	}
+	if got := p.navigation.GetCurrentState(); got != GUARDING_STATE {
+		t.Fatalf("state = %d, want GUARDING_STATE", got)
+	}
+
+	beeps, flashes := fr.Buzzer.Pulses, fr.StatusLed.Pulses
+	fr.Ultrasonic.Distance = 40
+	p.Tick()
+	if got := p.navigation.GetCurrentState(); got != ALERT_STATE {
+		t.Fatalf("state = %d, want ALERT_STATE", got)
+	}
+	if p.display.currentExpr != EXPR_SURPRISED {
+		t.Errorf("expression = %d, want EXPR_SURPRISED", p.display.currentExpr)
+	}
+	const alarmMs = 1500
+	runFor(p, fr, alarmMs)
+	if got, want := fr.Buzzer.Pulses-beeps, soundedNotes(SOUND_INTRUDER_ALARM); got != want || fr.Buzzer.Hz != 0 {
+		t.Errorf("alarm notes = %d (hz=%d), want %d and silent", got, fr.Buzzer.Hz, want)
+	}
+	if got, want := fr.StatusLed.Pulses-flashes, soundedNotes(SOUND_INTRUDER_ALARM); got < want {
+		t.Errorf("LED strobes = %d, want at least %d", got, want)
+	}
+	if fr.LeftMotor.Speed != 0 || fr.RightMotor.Speed != 0 {
+		t.Error("motors moved in guard mode")
+	}
+
+	for i := 0; i < navlogic.GuardAlertTicks-alarmMs/NAV_PERIOD_MS; i++ {
+		p.Tick()
+	}
+	if got := p.navigation.GetCurrentState(); got != GUARDING_STATE {
+		t.Errorf("state after alert = %d, want GUARDING_STATE", got)
+	}
+}
+
+func TestTick_InteractiveModePetGesture(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	p.Navigation().SetBehaviorMode(INTERACTIVE_MODE)
+
+	for _, d := range []int{60, 5, 60, 5, 60} {
+		fr.Ultrasonic.Distance = d
+		p.Tick()
+	}
+	if got := p.navigation.GetCurrentState(); got != INTERACTING_STATE {
+		t.Fatalf("state = %d, want INTERACTING_STATE", got)
+	}
+	if p.display.currentExpr != EXPR_EXCITED {
+		t.Errorf("expression = %d, want EXPR_EXCITED", p.display.currentExpr)
+	}
+	runFor(p, fr, 1000)
+	if want := soundedNotes(SOUND_HAPPY_CHIRP); fr.Buzzer.Pulses != want {
+		t.Errorf("buzzer pulses = %d, want %d", fr.Buzzer.Pulses, want)
+	}
+
+	for i := 0; i < navlogic.InteractTicks; i++ {
+		p.Tick()
+	}
+	if got := p.navigation.GetCurrentState(); got != MOVING_STATE {
+		t.Errorf("state after interaction = %d, want MOVING_STATE", got)
+	}
+}
+
+func TestTick_RandomWalkIgnoresPetGesture(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+
+	for _, d := range []int{60, 5, 60, 5, 60, 60} {
+		fr.Ultrasonic.Distance = d
+		p.Tick()
+		if got := p.navigation.GetCurrentState(); got == INTERACTING_STATE {
+			t.Fatal("random walk entered INTERACTING_STATE")
+		}
+	}
+}
+
+func TestTick_RepeatedEdgeScaresFrightenThePet(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	p.Tick()
+
+	escapeTicks := int((EDGE_REVERSE_MS+EDGE_TURN_MS)/FAKE_TICK_MS) + 1
+	for i := 0; i < 3; i++ {
+		fr.IRSensors[IR_FRONT_LEFT].Value = 0
+		p.Tick()
+		fr.IRSensors[IR_FRONT_LEFT].Value = FAKE_SURFACE_READING
+		for j := 0; j < escapeTicks; j++ {
+			p.Tick()
+		}
+	}
+	if got := p.navigation.GetCurrentState(); got != MOVING_STATE {
+		t.Fatalf("state = %d, want MOVING_STATE", got)
+	}
+	if got := p.Mood().Feeling(); got != navlogic.FeelingAfraid {
+		t.Fatalf("feeling = %d (%+v), want FeelingAfraid", got, p.Mood())
+	}
+	if got := p.display.GetCurrentExpression(); got != EXPR_SCARED {
+		t.Errorf("expression = %d, want EXPR_SCARED while wandering afraid", got)
+	}
+	if want := navlogic.ScaleCruiseSpeed(MAX_SPEED, navlogic.AfraidSpeedPct); fr.LeftMotor.Speed > want {
+		t.Errorf("speed = %d, want at most %d while afraid", fr.LeftMotor.Speed, want)
+	}
+
+	for i := 0; i < 20*navlogic.MoodStepMs/FAKE_TICK_MS; i++ {
+		p.Tick()
+	}
+	if got := p.display.GetCurrentExpression(); got != EXPR_HAPPY {
+		t.Errorf("expression = %d after calming down, want EXPR_HAPPY", got)
+	}
+}
+
+func TestTick_TiredPetYawnsAndSlows(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	p.Tick()
+	beeps := fr.Buzzer.Pulses
+
+	p.mood.Energy = navlogic.TiredEnergy
+	p.Tick()
+	runFor(p, fr, 1000)
+	if got := p.display.GetCurrentExpression(); got != EXPR_SLEEPY {
+		t.Errorf("expression = %d, want EXPR_SLEEPY", got)
+	}
+	if got, want := fr.Buzzer.Pulses-beeps, soundedNotes(SOUND_SLEEPY_YAWN); got != want {
+		t.Errorf("notes = %d, want a %d-note yawn", got, want)
+	}
+	if want := navlogic.ScaleCruiseSpeed(MAX_SPEED, navlogic.TiredSpeedPct); fr.LeftMotor.Speed > want {
+		t.Errorf("speed = %d, want at most %d while tired", fr.LeftMotor.Speed, want)
+	}
+}
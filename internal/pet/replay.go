@@ -0,0 +1,87 @@
+//go:build !tinygo
+
+package pet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/console"
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+// ReplayStep is what the pet was doing after one trace tick.
+type ReplayStep struct {
+	TimeMs      uint32
+	State       int
+	Left, Right int
+}
+
+// Replay runs a trace through a Pet on fake hardware: every tick sets the sensors to the recorded readings
+// and runs Tick at the recorded time. It returns the navigation state and commanded wheel speeds after
+// each tick. The pet is created at the trace's start time as after power-on, without the calibration or a
+// battery monitor.
+func Replay(trace *Trace) []ReplayStep {
+	fr := NewFakeRobot()
+	fr.Robot.Battery = nil
+	fr.Robot.Serial = nil
+	fr.Clock.Step = 0
+	fr.Clock.Now = trace.StartMs
+	p := New(fr.Robot)
+	p.sensors.SetObstacleThreshold(trace.ObstacleCm)
+	p.sensors.SetEdgeThresholds(trace.EdgeThresholds)
+
+	steps := make([]ReplayStep, 0, len(trace.Ticks))
+	for _, tick := range trace.Ticks {
+		fr.Clock.Now = tick.TimeMs
+		fr.Ultrasonic.Status = navlogic.RangeOK
+		if tick.Distance == TRACE_SONAR_FAULT {
+			fr.Ultrasonic.Status = navlogic.RangeNoEcho
+		} else {
+			fr.Ultrasonic.Distance = tick.Distance
+		}
+		for i, v := range tick.IR {
+			if v == TRACE_NO_SENSOR {
+				fr.Robot.IRSensors[i] = nil
+				continue
+			}
+			fr.IRSensors[i].Value = uint16(v)
+			fr.Robot.IRSensors[i] = fr.IRSensors[i]
+		}
+		p.navigation.SetBehaviorMode(tick.Mode)
+
+		p.Tick()
+		left, right := p.motors.GetWheelSpeeds()
+		steps = append(steps, ReplayStep{TimeMs: tick.TimeMs, State: p.navigation.GetCurrentState(), Left: left, Right: right})
+	}
+	return steps
+}
+
+// Check compares the steps Replay returned for t with its expect lines. Each expectation is checked
+// against the first step at or after its time.
+func (t *Trace) Check(steps []ReplayStep) error {
+	var errs []error
+	for _, e := range t.Expects {
+		i := 0
+		for i < len(steps) && steps[i].TimeMs < e.TimeMs {
+			i++
+		}
+		if i == len(steps) {
+			errs = append(errs, fmt.Errorf("line %d: trace ends before %d ms", e.Line, e.TimeMs))
+			continue
+		}
+		s := steps[i]
+		if s.State != e.State || e.Wheels && (s.Left != e.Left || s.Right != e.Right) {
+			errs = append(errs, fmt.Errorf("line %d: at %d ms got %s %d %d, want %s",
+				e.Line, s.TimeMs, console.StateNames[s.State], s.Left, s.Right, e.describe()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e TraceExpect) describe() string {
+	if e.Wheels {
+		return fmt.Sprintf("%s %d %d", console.StateNames[e.State], e.Left, e.Right)
+	}
+	return console.StateNames[e.State]
+}
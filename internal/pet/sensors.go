@@ -0,0 +1,182 @@
+package pet
+
+import (
+	"github.com/GyeongHoKim/tiny-pet/internal/logging"
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+const (
+	OBSTACLE_DISTANCE_THRESHOLD = 20
+	EDGE_DETECTION_THRESHOLD    = 500
+	EDGE_CAUTION_FACTOR         = 2
+)
+
+// IR sensor slots; the rear pair is optional and left nil when not fitted.
+const (
+	IR_FRONT_LEFT = iota
+	IR_FRONT_RIGHT
+	IR_REAR_LEFT
+	IR_REAR_RIGHT
+	IR_SENSOR_COUNT
+)
+
+// SensorModule reads ultrasonic (HC-SR04) and IR edge sensors.
+type SensorModule struct {
+	ultrasonic        DistanceSensor
+	irSensors         *EdgeSensorArray
+	obstacleThreshold int
+	edgeThresholds    [IR_SENSOR_COUNT]uint16
+	distanceOffset    int
+	distanceFilter    navlogic.DistanceFilter
+	obstacle          bool
+	sonarHealth       navlogic.SonarHealth
+	lastDistance      int
+	lastFault         bool
+}
+
+func NewSensorModule(ultrasonic DistanceSensor, irSensors *EdgeSensorArray) *SensorModule {
+	s := &SensorModule{
+		ultrasonic:        ultrasonic,
+		irSensors:         irSensors,
+		obstacleThreshold: OBSTACLE_DISTANCE_THRESHOLD,
+		lastDistance:      -1,
+	}
+	for i := range s.edgeThresholds {
+		s.edgeThresholds[i] = EDGE_DETECTION_THRESHOLD
+	}
+	return s
+}
+
+// SetThresholds overrides the obstacle distance (cm) and sets every IR edge threshold (raw ADC) to edge.
+func (s *SensorModule) SetThresholds(obstacleCm int, edge uint16) {
+	s.obstacleThreshold = obstacleCm
+	for i := range s.edgeThresholds {
+		s.edgeThresholds[i] = edge
+	}
+}
+
+func (s *SensorModule) SetObstacleThreshold(obstacleCm int) {
+	s.obstacleThreshold = obstacleCm
+}
+
+// SetEdgeThresholds sets per-sensor IR edge thresholds (raw ADC); a threshold of 0 disables that sensor.
+func (s *SensorModule) SetEdgeThresholds(edge [IR_SENSOR_COUNT]uint16) {
+	s.edgeThresholds = edge
+}
+
+func (s *SensorModule) GetThresholds() (obstacleCm int, edge [IR_SENSOR_COUNT]uint16) {
+	return s.obstacleThreshold, s.edgeThresholds
+}
+
+// SetDistanceOffset sets the cm added to every valid ultrasonic reading to correct for sensor mounting.
+func (s *SensorModule) SetDistanceOffset(cm int) {
+	s.distanceOffset = cm
+}
+
+func (s *SensorModule) GetDistanceOffset() int {
+	return s.distanceOffset
+}
+
+// ReadUltrasonicDistance returns distance in cm, or -1 when nothing is in range or the sensor faulted.
+// Every reading also updates the sensor health (IsSonarDegraded).
+func (s *SensorModule) ReadUltrasonicDistance() int {
+	reading := s.ultrasonic.ReadRange()
+	s.sonarHealth.Update(reading)
+	s.lastFault = reading.IsFault()
+	distance := reading.Distance()
+	if distance >= 0 {
+		distance += s.distanceOffset
+		if distance < 0 {
+			distance = 0
+		}
+	}
+	s.lastDistance = distance
+	return distance
+}
+
+// LastDistance returns the distance from the latest ReadUltrasonicDistance without pinging again.
+func (s *SensorModule) LastDistance() int {
+	return s.lastDistance
+}
+
+// LastFault reports whether the latest ReadUltrasonicDistance was a sensor fault rather than nothing in range.
+func (s *SensorModule) LastFault() bool {
+	return s.lastFault
+}
+
+// IsSonarDegraded reports whether the ultrasonic sensor has faulted repeatedly (navlogic.SonarHealth),
+// so its distances cannot be trusted.
+func (s *SensorModule) IsSonarDegraded() bool {
+	return s.sonarHealth.Degraded()
+}
+
+// FilterDistance feeds a reading from ReadUltrasonicDistance through the spike/median/smoothing filter
+// and returns the filtered distance in cm, or -1 when nothing is in range.
+func (s *SensorModule) FilterDistance(raw int) int {
+	return s.distanceFilter.Update(raw)
+}
+
+func (s *SensorModule) IsObstacleDetected() bool {
+	return s.IsObstacle(s.FilterDistance(s.ReadUltrasonicDistance()))
+}
+
+// IsObstacle reports whether a filtered distance is within the obstacle threshold. Once an obstacle is seen
+// it is held until the distance clears the threshold by navlogic.ObstacleHysteresisCm.
+func (s *SensorModule) IsObstacle(distance int) bool {
+	obstacle := navlogic.IsObstacleWithHysteresis(distance, s.obstacleThreshold, s.obstacle)
+	if obstacle && !s.obstacle {
+		logging.Trace(logging.Sensors, "Obstacle", distance)
+	}
+	s.obstacle = obstacle
+	return s.obstacle
+}
+
+// IsEdgeDetected reports whether either front IR sensor sees no surface below.
+func (s *SensorModule) IsEdgeDetected() bool {
+	return s.isEdge(IR_FRONT_LEFT) || s.isEdge(IR_FRONT_RIGHT)
+}
+
+// IsRearEdgeDetected reports whether a fitted rear IR sensor sees no surface below.
+func (s *SensorModule) IsRearEdgeDetected() bool {
+	return s.isEdge(IR_REAR_LEFT) || s.isEdge(IR_REAR_RIGHT)
+}
+
+// IsNearEdge reports whether a front IR reading is within EDGE_CAUTION_FACTOR of its edge threshold, so the pet should creep.
+func (s *SensorModule) IsNearEdge() bool {
+	for _, i := range [...]int{IR_FRONT_LEFT, IR_FRONT_RIGHT} {
+		caution := uint32(s.edgeThresholds[i]) * EDGE_CAUTION_FACTOR
+		if s.irSensors[i] != nil && uint32(s.irSensors[i].Get()) < caution {
+			return true
+		}
+	}
+	return false
+}
+
+// HasIRSensor reports whether a sensor is fitted in slot i.
+func (s *SensorModule) HasIRSensor(i int) bool {
+	return s.irSensors[i] != nil
+}
+
+// ReadIRRaw returns the raw ADC reading of every sensor slot; slots without a sensor read 0.
+func (s *SensorModule) ReadIRRaw() [IR_SENSOR_COUNT]uint16 {
+	var raw [IR_SENSOR_COUNT]uint16
+	for i := 0; i < IR_SENSOR_COUNT; i++ {
+		if s.irSensors[i] != nil {
+			raw[i] = s.irSensors[i].Get()
+		}
+	}
+	return raw
+}
+
+// ReadIRSensors reports the edge state of every sensor slot; slots without a sensor read false.
+func (s *SensorModule) ReadIRSensors() [IR_SENSOR_COUNT]bool {
+	var results [IR_SENSOR_COUNT]bool
+	for i := 0; i < IR_SENSOR_COUNT; i++ {
+		results[i] = s.isEdge(i)
+	}
+	return results
+}
+
+func (s *SensorModule) isEdge(i int) bool {
+	return s.irSensors[i] != nil && s.irSensors[i].Get() < s.edgeThresholds[i]
+}
@@ -0,0 +1,93 @@
+package pet
+
+import (
+	"github.com/GyeongHoKim/tiny-pet/internal/logging"
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+// SetSleepAfter sets how long the pet wanders before it parks and falls asleep; 0 keeps it awake.
+func (p *Pet) SetSleepAfter(ms uint32) {
+	p.sleepTimer.AfterMs = ms
+}
+
+func (p *Pet) IsAsleep() bool {
+	return p.asleep
+}
+
+// Rest waits for the next main-loop pass in low-power idle: loopMs while awake, navlogic.SleepCheckMs
+// between sensor checks while asleep or on a flat battery (once the last sound has played). Asleep, it
+// wakes every navlogic.WakePollMs to poll the wake button and returns as soon as it is pressed.
+func (p *Pet) Rest(loopMs uint32) {
+	if (p.asleep || p.isBatteryFlat()) && !p.behaviors.Busy() {
+		loopMs = navlogic.SleepCheckMs
+	}
+	if !p.asleep || p.robot.WakeButton == nil {
+		p.robot.Power.Idle(loopMs)
+		return
+	}
+	for loopMs > 0 && !p.wakePressed {
+		ms := min(loopMs, navlogic.WakePollMs)
+		p.robot.Power.Idle(ms)
+		loopMs -= ms
+		p.wakePressed = p.robot.WakeButton.Pressed()
+	}
+}
+
+// fallAsleep parks the pet with the sleeping face; navigation stops until it wakes.
+func (p *Pet) fallAsleep() {
+	logging.Info(logging.Power, "Falling asleep")
+	p.asleep = true
+	p.navigation.EmergencyStop()
+	p.sleepCycle.Reset()
+	p.sleepCheckMs = 0
+	p.wakePressed = false
+	p.screenOn = true
+	p.zzz = p.sleepCycle.ZzzCount()
+	p.behaviors.IndicateSleep()
+	p.display.ShowSleeping(p.zzz)
+}
+
+// dream runs a main-loop pass while asleep: the Zzz animation, the screen on/off cycle, a wake button
+// press seen by Rest and, every navlogic.SleepCheckMs, a check for something close to the ultrasonic
+// sensor or the button held down.
+func (p *Pet) dream(elapsed uint32) {
+	p.sleepCycle.Advance(elapsed)
+	if on := p.sleepCycle.ScreenOn(); on != p.screenOn {
+		p.screenOn = on
+		p.display.SetScreen(on)
+	}
+	if zzz := p.sleepCycle.ZzzCount(); p.screenOn && zzz != p.zzz {
+		p.zzz = zzz
+		p.display.ShowSleeping(zzz)
+	}
+
+	p.sleepCheckMs += elapsed
+	if p.wakePressed {
+		p.wake()
+		return
+	}
+	if p.sleepCheckMs < navlogic.SleepCheckMs {
+		return
+	}
+	p.sleepCheckMs = 0
+	pressed := p.robot.WakeButton != nil && p.robot.WakeButton.Pressed()
+	if navlogic.ShouldWake(p.sensors.ReadUltrasonicDistance(), pressed) {
+		p.wake()
+	}
+}
+
+// wake restores the screen and starts navigation again from idle, rested.
+func (p *Pet) wake() {
+	logging.Info(logging.Power, "Waking up")
+	p.asleep = false
+	p.wakePressed = false
+	if !p.screenOn {
+		p.screenOn = true
+		p.display.SetScreen(true)
+	}
+	p.sleepTimer.Reset()
+	p.mood.Rest()
+	p.navElapsed = 0
+	p.lastState = -1
+	p.behaviors.IndicateWake()
+}
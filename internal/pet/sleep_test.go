@@ -0,0 +1,165 @@
+package pet
+
+import (
+	"testing"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+const testSleepAfterMs = 2000
+
+// asleepPet returns a pet that wandered until it fell asleep.
+func asleepPet(t *testing.T) (*Pet, *FakeRobot) {
+	t.Helper()
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	p.SetSleepAfter(testSleepAfterMs)
+	for i := 0; i < testSleepAfterMs/FAKE_TICK_MS+1 && !p.IsAsleep(); i++ {
+		p.Tick()
+	}
+	if !p.IsAsleep() {
+		t.Fatal("still awake after wandering for the sleep period")
+	}
+	return p, fr
+}
+
+func TestSleep_FallsAsleepAfterWandering(t *testing.T) {
+	p, fr := asleepPet(t)
+
+	if fr.LeftMotor.Speed != 0 || fr.RightMotor.Speed != 0 {
+		t.Errorf("motors = (%d, %d), want parked", fr.LeftMotor.Speed, fr.RightMotor.Speed)
+	}
+	if got := p.display.GetCurrentExpression(); got != EXPR_SLEEPING {
+		t.Errorf("expression = %d, want EXPR_SLEEPING", got)
+	}
+	beeps := fr.Buzzer.Pulses
+	runFor(p, fr, 1000)
+	if got, want := fr.Buzzer.Pulses-beeps, soundedNotes(SOUND_SLEEPY_YAWN)-1; got != want {
+		t.Errorf("notes after falling asleep = %d, want the rest of the %d-note yawn", got, want+1)
+	}
+	if fr.StatusLed.On {
+		t.Error("LED on while asleep")
+	}
+}
+
+func TestSleep_ZzzAndScreenCycle(t *testing.T) {
+	p, fr := asleepPet(t)
+	frames := fr.Display.Frames
+
+	fr.Clock.Step = navlogic.SleepCheckMs
+	for i := 0; i < navlogic.SleepScreenOnMs/navlogic.SleepCheckMs; i++ {
+		p.Tick()
+	}
+	if !fr.Display.Off {
+		t.Error("screen still on after SleepScreenOnMs asleep")
+	}
+	if fr.Display.Frames == frames {
+		t.Error("Zzz never animated")
+	}
+	frames = fr.Display.Frames
+	for i := 0; i < navlogic.SleepScreenOffMs/navlogic.SleepCheckMs-1; i++ {
+		p.Tick()
+	}
+	if fr.Display.Frames != frames {
+		t.Errorf("%d frames drawn with the screen off, want none", fr.Display.Frames-frames)
+	}
+	p.Tick()
+	if fr.Display.Off {
+		t.Error("screen still off after SleepScreenOffMs")
+	}
+	if !p.IsAsleep() || fr.LeftMotor.Speed != 0 {
+		t.Error("woke up or moved with nothing nearby")
+	}
+}
+
+func TestSleep_Wakes(t *testing.T) {
+	tests := []struct {
+		name string
+		wake func(fr *FakeRobot)
+	}{
+		{"hand near the ultrasonic sensor", func(fr *FakeRobot) { fr.Ultrasonic.Distance = navlogic.SleepWakeCm - 5 }},
+		{"button press", func(fr *FakeRobot) { fr.WakeButton.Down = true }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, fr := asleepPet(t)
+			fr.Clock.Step = navlogic.SleepCheckMs
+			for i := 0; i < 8; i++ {
+				p.Tick()
+			}
+			p.mood.Energy = 0
+
+			tt.wake(fr)
+			p.Tick()
+			if p.IsAsleep() {
+				t.Fatal("still asleep")
+			}
+			fr.Ultrasonic.Distance = navlogic.TimeoutDistance
+			fr.WakeButton.Down = false
+			fr.Clock.Step = FAKE_TICK_MS
+			p.Tick()
+			p.Tick()
+			if got := p.navigation.GetCurrentState(); got != MOVING_STATE {
+				t.Errorf("state = %d after waking, want MOVING_STATE", got)
+			}
+			if got := p.display.GetCurrentExpression(); got != EXPR_HAPPY {
+				t.Errorf("expression = %d after waking, want EXPR_HAPPY", got)
+			}
+			if fr.Display.Off {
+				t.Error("screen still off after waking")
+			}
+			if p.Mood().Energy != navlogic.MoodMax {
+				t.Errorf("energy = %d after sleeping, want %d", p.Mood().Energy, navlogic.MoodMax)
+			}
+		})
+	}
+}
+
+func TestSleep_WakesOnTapBetweenChecks(t *testing.T) {
+	p, fr := asleepPet(t)
+	runFor(p, fr, 1000)
+	// The button is down for a single poll in the middle of the rest.
+	fr.Power.IdleMs = 0
+	fr.Power.OnIdle = func() { fr.WakeButton.Down = fr.Power.Calls == 3 }
+	p.Rest(10)
+	fr.Power.OnIdle = nil
+	fr.WakeButton.Down = false
+	if fr.Power.IdleMs != 3*navlogic.WakePollMs {
+		t.Errorf("rested %d ms, want %d: the press should end the rest", fr.Power.IdleMs, 3*navlogic.WakePollMs)
+	}
+	fr.Clock.Step = 3 * navlogic.WakePollMs
+	p.Tick()
+	if p.IsAsleep() {
+		t.Error("slept through a tap between checks")
+	}
+}
+
+func TestSleep_NotInGuardMode(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	p.SetSleepAfter(testSleepAfterMs)
+	p.Navigation().SetBehaviorMode(GUARD_MODE)
+	for i := 0; i < 3*testSleepAfterMs/FAKE_TICK_MS; i++ {
+		p.Tick()
+	}
+	if p.IsAsleep() {
+		t.Error("fell asleep while guarding")
+	}
+}
+
+func TestRest(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	p.Rest(10)
+	if fr.Power.IdleMs != 10 {
+		t.Errorf("awake rest = %d ms, want 10", fr.Power.IdleMs)
+	}
+
+	p, fr = asleepPet(t)
+	runFor(p, fr, 1000)
+	fr.Power.IdleMs = 0
+	p.Rest(10)
+	if fr.Power.IdleMs != navlogic.SleepCheckMs {
+		t.Errorf("asleep rest = %d ms, want SleepCheckMs", fr.Power.IdleMs)
+	}
+}
@@ -0,0 +1,70 @@
+package pet
+
+import (
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+// SetTelemetry turns the telemetry stream on or off: with it on, every Tick writes one navlogic telemetry
+// frame to the serial port. The console's "telemetry on|off" does the same.
+func (p *Pet) SetTelemetry(on bool) {
+	p.telemetryOn = on
+}
+
+func (p *Pet) TelemetryOn() bool {
+	return p.telemetryOn
+}
+
+// sendTelemetry writes a frame describing the tick that started at now, elapsed after the previous one.
+// The distance is the last one navigation measured, so no extra ping is made.
+// The frame says whether that reading was a fault and which IR sensors are fitted, so a capture replays exactly.
+func (p *Pet) sendTelemetry(now, elapsed uint32) {
+	port := p.robot.Serial
+	if port == nil {
+		return
+	}
+	left, right := p.motors.GetWheelSpeeds()
+	s := navlogic.TelemetrySample{
+		Seq:        p.telemetrySeq,
+		TimeMs:     now,
+		State:      uint8(p.navigation.GetCurrentState()),
+		Mode:       uint8(p.navigation.GetBehaviorMode()),
+		Flags:      p.telemetryFlags(),
+		Distance:   int16(p.sensors.LastDistance()),
+		LeftMotor:  int8(left),
+		RightMotor: int8(right),
+		LoopMs:     uint16(elapsed),
+		BusyMs:     uint16(p.robot.Clock.Millis() - now),
+	}
+	s.IR = p.sensors.ReadIRRaw()
+	for i := 0; i < IR_SENSOR_COUNT; i++ {
+		if p.sensors.HasIRSensor(i) {
+			s.IRFitted |= 1 << i
+		}
+	}
+	s.Encode(&p.frame)
+	port.Write(p.frame[:])
+	p.telemetrySeq++
+}
+
+func (p *Pet) telemetryFlags() uint8 {
+	var flags uint8
+	if p.manual {
+		flags |= navlogic.TelemetryFlagManual
+	}
+	if p.asleep {
+		flags |= navlogic.TelemetryFlagAsleep
+	}
+	if p.degraded {
+		flags |= navlogic.TelemetryFlagSonarDegraded
+	}
+	if p.sensors.LastFault() {
+		flags |= navlogic.TelemetryFlagSonarFault
+	}
+	switch p.batteryLevel {
+	case navlogic.BatteryLow:
+		flags |= navlogic.TelemetryFlagBatteryLow
+	case navlogic.BatteryCritical:
+		flags |= navlogic.TelemetryFlagBatteryFlat
+	}
+	return flags
+}
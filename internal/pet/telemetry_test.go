@@ -0,0 +1,136 @@
+package pet
+
+import (
+	"testing"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+// frames decodes every telemetry frame the pet has written, skipping console replies.
+func frames(t *testing.T, fr *FakeRobot) []navlogic.TelemetrySample {
+	t.Helper()
+	var samples []navlogic.TelemetrySample
+	data := fr.Serial.Out.Bytes()
+	for {
+		s, used, _, ok := navlogic.NextTelemetryFrame(data)
+		data = data[used:]
+		if !ok {
+			break
+		}
+		samples = append(samples, s)
+	}
+	return samples
+}
+
+func TestTelemetry_OffByDefault(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	runFor(p, fr, 500)
+	if fr.Serial.Out.Len() != 0 {
+		t.Errorf("wrote %d bytes with telemetry off", fr.Serial.Out.Len())
+	}
+}
+
+func TestTelemetry_FramePerTick(t *testing.T) {
+	fr := NewFakeRobot()
+	fr.Ultrasonic.Distance = 50
+	p := New(fr.Robot)
+	p.SetTelemetry(true)
+	for i := 0; i < 20; i++ {
+		p.Tick()
+	}
+
+	got := frames(t, fr)
+	if len(got) != 20 {
+		t.Fatalf("%d frames, want one per tick (20)", len(got))
+	}
+	for i, s := range got {
+		if s.Seq != uint16(i) {
+			t.Fatalf("frame %d has sequence number %d", i, s.Seq)
+		}
+	}
+	last := got[len(got)-1]
+	if last.State != navlogic.StateMoving || last.Mode != RANDOM_WALK_MODE {
+		t.Errorf("state, mode = %d, %d; want moving in random walk", last.State, last.Mode)
+	}
+	if last.Distance != 50 {
+		t.Errorf("distance = %d, want 50", last.Distance)
+	}
+	if last.IR != [navlogic.TelemetryIRSensors]uint16{FAKE_SURFACE_READING, FAKE_SURFACE_READING, FAKE_SURFACE_READING, FAKE_SURFACE_READING} {
+		t.Errorf("IR = %v, want every sensor over the surface", last.IR)
+	}
+	if last.IRFitted != 0b1111 {
+		t.Errorf("IR fitted = %04b, want every sensor", last.IRFitted)
+	}
+	left, right := p.motors.GetWheelSpeeds()
+	if int(last.LeftMotor) != left || int(last.RightMotor) != right || left == 0 {
+		t.Errorf("motors = (%d, %d), want the commanded (%d, %d)", last.LeftMotor, last.RightMotor, left, right)
+	}
+	// The fake clock moves on every read: the tick start, then the busy time at the end.
+	if last.LoopMs != 2*FAKE_TICK_MS || last.BusyMs != FAKE_TICK_MS {
+		t.Errorf("loop, busy = %d, %d ms; want %d, %d", last.LoopMs, last.BusyMs, 2*FAKE_TICK_MS, FAKE_TICK_MS)
+	}
+	if last.TimeMs <= got[0].TimeMs {
+		t.Errorf("time did not advance: %d then %d", got[0].TimeMs, last.TimeMs)
+	}
+}
+
+func TestTelemetry_Flags(t *testing.T) {
+	fr := NewFakeRobot()
+	fr.Battery.Mv = testFlatBatteryMv
+	p := New(fr.Robot)
+	command(p, fr, "stop")
+	command(p, fr, "telemetry on")
+	p.Tick()
+
+	got := frames(t, fr)
+	if len(got) == 0 {
+		t.Fatal("no telemetry after \"telemetry on\"")
+	}
+	want := uint8(navlogic.TelemetryFlagManual | navlogic.TelemetryFlagBatteryFlat)
+	if flags := got[len(got)-1].Flags; flags != want {
+		t.Errorf("flags = %#x, want %#x", flags, want)
+	}
+}
+
+func TestTelemetry_SonarFaultAndMissingIR(t *testing.T) {
+	fr := NewFakeRobot()
+	fr.Robot.IRSensors[IR_REAR_RIGHT] = nil
+	p := New(fr.Robot)
+	p.SetTelemetry(true)
+	p.Tick()
+	fr.Ultrasonic.Status = navlogic.RangeNoEcho
+	p.Tick()
+
+	got := frames(t, fr)
+	if len(got) != 2 {
+		t.Fatalf("%d frames, want 2", len(got))
+	}
+	for i, wantFault := range []bool{false, true} {
+		s := got[i]
+		if fault := s.Flags&navlogic.TelemetryFlagSonarFault != 0; fault != wantFault {
+			t.Errorf("frame %d: sonar fault flag %v, want %v", i, fault, wantFault)
+		}
+		if s.IRFitted != 0b0111 {
+			t.Errorf("frame %d: IR fitted = %04b, want all but the rear right", i, s.IRFitted)
+		}
+	}
+	if got[1].Distance != navlogic.TimeoutDistance {
+		t.Errorf("fault sent as distance %d, want %d", got[1].Distance, navlogic.TimeoutDistance)
+	}
+}
+
+func TestTelemetry_ConsoleOff(t *testing.T) {
+	fr := NewFakeRobot()
+	p := New(fr.Robot)
+	p.SetTelemetry(true)
+	p.Tick()
+	if got := command(p, fr, "telemetry off"); got[0] != "ok" {
+		t.Fatalf("reply = %q, want ok", got)
+	}
+	fr.Serial.Out.Reset()
+	runFor(p, fr, 200)
+	if p.TelemetryOn() || fr.Serial.Out.Len() != 0 {
+		t.Errorf("wrote %d bytes after \"telemetry off\"", fr.Serial.Out.Len())
+	}
+}
@@ -0,0 +1,274 @@
+//go:build !tinygo
+
+package pet
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/console"
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+// Trace text format, one line per main-loop tick; blank lines and "#" comments are ignored:
+//
+//	<time ms> <mode> <distance cm> <ir0> <ir1> <ir2> <ir3>
+//
+// mode is a console mode name (walk, guard, interactive). distance is -1 when nothing is in range and
+// "fault" when the sonar did not echo. An IR reading is the raw ADC value, or "-" for a sensor not fitted.
+// Setting lines apply to the whole trace; expect lines are checked by Trace.Check after Replay:
+//
+//	start <time ms>                           when the pet was created (default: the first tick's time)
+//	obstacle <cm>                             obstacle threshold
+//	edge <t0> <t1> <t2> <t3>                  IR edge thresholds
+//	expect <time ms> <state> [<left> <right>] navigation state and, if given, wheel speeds at that time
+const (
+	// TRACE_SONAR_FAULT is the distance of a tick on which the sonar did not echo.
+	TRACE_SONAR_FAULT = -2
+	// TRACE_NO_SENSOR is the IR reading of a sensor that is not fitted.
+	TRACE_NO_SENSOR = -1
+)
+
+// TraceTick is the sensor input of one main-loop tick.
+type TraceTick struct {
+	TimeMs   uint32
+	Mode     int
+	Distance int
+	IR       [IR_SENSOR_COUNT]int
+}
+
+// TraceExpect is what the pet should be doing at TimeMs; the wheel speeds are only checked when Wheels is set.
+type TraceExpect struct {
+	Line        int
+	TimeMs      uint32
+	State       int
+	Wheels      bool
+	Left, Right int
+}
+
+// Trace is a recorded run: when the pet started and the thresholds it ran with, its per-tick sensor readings and the
+// expectations a regression test checks.
+type Trace struct {
+	StartMs        uint32
+	ObstacleCm     int
+	EdgeThresholds [IR_SENSOR_COUNT]uint16
+	Ticks          []TraceTick
+	Expects        []TraceExpect
+}
+
+// NewTrace returns an empty trace with the firmware's default thresholds.
+func NewTrace() *Trace {
+	t := &Trace{ObstacleCm: OBSTACLE_DISTANCE_THRESHOLD}
+	for i := range t.EdgeThresholds {
+		t.EdgeThresholds[i] = EDGE_DETECTION_THRESHOLD
+	}
+	return t
+}
+
+var errTraceTime = errors.New("time goes backwards")
+
+// ReadTrace parses a trace; errors name the offending line.
+func ReadTrace(r io.Reader) (*Trace, error) {
+	t := NewTrace()
+	t.StartMs = noStart
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if err := t.parseLine(n, fields); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+	}
+	if t.StartMs == noStart {
+		t.StartMs = 0
+		if len(t.Ticks) > 0 {
+			t.StartMs = t.Ticks[0].TimeMs
+		}
+	}
+	return t, scanner.Err()
+}
+
+// noStart marks a trace without a start line while it is read.
+const noStart = ^uint32(0)
+
+func (t *Trace) parseLine(n int, fields []string) error {
+	switch fields[0] {
+	case "start":
+		if len(fields) != 2 {
+			return errors.New("want start <time ms>")
+		}
+		ms, err := strconv.ParseUint(fields[1], 10, 32)
+		t.StartMs = uint32(ms)
+		return err
+	case "obstacle":
+		if len(fields) != 2 {
+			return errors.New("want obstacle <cm>")
+		}
+		cm, err := strconv.Atoi(fields[1])
+		t.ObstacleCm = cm
+		return err
+	case "edge":
+		if len(fields) != 1+IR_SENSOR_COUNT {
+			return fmt.Errorf("want %d edge thresholds", IR_SENSOR_COUNT)
+		}
+		for i := range t.EdgeThresholds {
+			v, err := strconv.ParseUint(fields[1+i], 10, 16)
+			if err != nil {
+				return err
+			}
+			t.EdgeThresholds[i] = uint16(v)
+		}
+		return nil
+	case "expect":
+		return t.parseExpect(n, fields[1:])
+	}
+	return t.parseTick(fields)
+}
+
+func (t *Trace) parseTick(fields []string) error {
+	if len(fields) != 3+IR_SENSOR_COUNT {
+		return fmt.Errorf("want <time> <mode> <distance> and %d IR readings", IR_SENSOR_COUNT)
+	}
+	var tick TraceTick
+	ms, err := strconv.ParseUint(fields[0], 10, 32)
+	if err != nil {
+		return err
+	}
+	tick.TimeMs = uint32(ms)
+	if len(t.Ticks) > 0 && tick.TimeMs < t.Ticks[len(t.Ticks)-1].TimeMs {
+		return errTraceTime
+	}
+	if tick.Mode, err = lookupName(console.ModeNames[:], fields[1], "mode"); err != nil {
+		return err
+	}
+	if fields[2] == "fault" {
+		tick.Distance = TRACE_SONAR_FAULT
+	} else if tick.Distance, err = strconv.Atoi(fields[2]); err != nil || tick.Distance < navlogic.TimeoutDistance {
+		return fmt.Errorf("bad distance %q", fields[2])
+	}
+	for i := range tick.IR {
+		field := fields[3+i]
+		if field == "-" {
+			tick.IR[i] = TRACE_NO_SENSOR
+			continue
+		}
+		v, err := strconv.ParseUint(field, 10, 16)
+		if err != nil {
+			return err
+		}
+		tick.IR[i] = int(v)
+	}
+	t.Ticks = append(t.Ticks, tick)
+	return nil
+}
+
+func (t *Trace) parseExpect(n int, fields []string) error {
+	if len(fields) != 2 && len(fields) != 4 {
+		return errors.New("want expect <time> <state> [<left> <right>]")
+	}
+	e := TraceExpect{Line: n, Wheels: len(fields) == 4}
+	ms, err := strconv.ParseUint(fields[0], 10, 32)
+	if err != nil {
+		return err
+	}
+	e.TimeMs = uint32(ms)
+	if e.State, err = lookupName(console.StateNames[:], fields[1], "state"); err != nil {
+		return err
+	}
+	if e.Wheels {
+		if e.Left, err = strconv.Atoi(fields[2]); err != nil {
+			return err
+		}
+		if e.Right, err = strconv.Atoi(fields[3]); err != nil {
+			return err
+		}
+	}
+	t.Expects = append(t.Expects, e)
+	return nil
+}
+
+func lookupName(names []string, s, what string) (int, error) {
+	for i, name := range names {
+		if name == s {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown %s %q", what, s)
+}
+
+// Write writes the trace in the text format ReadTrace parses.
+func (t *Trace) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "start %d\nobstacle %d\nedge", t.StartMs, t.ObstacleCm)
+	for _, v := range t.EdgeThresholds {
+		fmt.Fprintf(bw, " %d", v)
+	}
+	fmt.Fprintf(bw, "\n# time mode distance ir0 ir1 ir2 ir3\n")
+	for _, tick := range t.Ticks {
+		distance := strconv.Itoa(tick.Distance)
+		if tick.Distance == TRACE_SONAR_FAULT {
+			distance = "fault"
+		}
+		fmt.Fprintf(bw, "%d %s %s", tick.TimeMs, console.ModeNames[tick.Mode], distance)
+		for _, v := range tick.IR {
+			if v == TRACE_NO_SENSOR {
+				fmt.Fprint(bw, " -")
+			} else {
+				fmt.Fprintf(bw, " %d", v)
+			}
+		}
+		fmt.Fprintln(bw)
+	}
+	for _, e := range t.Expects {
+		fmt.Fprintf(bw, "expect %d %s", e.TimeMs, console.StateNames[e.State])
+		if e.Wheels {
+			fmt.Fprintf(bw, " %d %d", e.Left, e.Right)
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
+
+// TraceFromTelemetry turns a telemetry capture into a trace with the default thresholds, up to the first
+// restart of the board. The pet is taken to start one loop period before the first frame. Readings flagged
+// navlogic.TelemetryFlagSonarFault become sonar faults and sensors missing from the IR fitted mask become
+// TRACE_NO_SENSOR.
+func TraceFromTelemetry(samples []navlogic.TelemetrySample) *Trace {
+	t := NewTrace()
+	for i := 1; i < len(samples); i++ {
+		if samples[i].TimeMs < samples[i-1].TimeMs {
+			samples = samples[:i]
+			break
+		}
+	}
+	if len(samples) > 0 {
+		t.StartMs = samples[0].TimeMs - uint32(samples[0].LoopMs)
+	}
+	for _, s := range samples {
+		tick := TraceTick{TimeMs: s.TimeMs, Mode: int(s.Mode), Distance: int(s.Distance)}
+		if tick.Mode >= len(console.ModeNames) {
+			tick.Mode = RANDOM_WALK_MODE
+		}
+		if s.Flags&navlogic.TelemetryFlagSonarFault != 0 {
+			tick.Distance = TRACE_SONAR_FAULT
+		}
+		for i, v := range s.IR {
+			tick.IR[i] = int(v)
+			if s.IRFitted&(1<<i) == 0 {
+				tick.IR[i] = TRACE_NO_SENSOR
+			}
+		}
+		t.Ticks = append(t.Ticks, tick)
+	}
+	return t
+}
@@ -0,0 +1,216 @@
+package pet
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+const testTrace = `# comment
+obstacle 25
+edge 600 600 550 550
+
+0 walk -1 40000 40000 - -   # rear sensors not fitted
+100 walk 30 40000 100 - -
+200 guard fault 0 0 - -
+expect 100 moving
+expect 200 guarding 0 0
+`
+
+func TestReadTrace(t *testing.T) {
+	trace, err := ReadTrace(strings.NewReader(testTrace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Trace{
+		StartMs:        0,
+		ObstacleCm:     25,
+		EdgeThresholds: [IR_SENSOR_COUNT]uint16{600, 600, 550, 550},
+		Ticks: []TraceTick{
+			{TimeMs: 0, Mode: RANDOM_WALK_MODE, Distance: -1, IR: [IR_SENSOR_COUNT]int{40000, 40000, TRACE_NO_SENSOR, TRACE_NO_SENSOR}},
+			{TimeMs: 100, Mode: RANDOM_WALK_MODE, Distance: 30, IR: [IR_SENSOR_COUNT]int{40000, 100, TRACE_NO_SENSOR, TRACE_NO_SENSOR}},
+			{TimeMs: 200, Mode: GUARD_MODE, Distance: TRACE_SONAR_FAULT, IR: [IR_SENSOR_COUNT]int{0, 0, TRACE_NO_SENSOR, TRACE_NO_SENSOR}},
+		},
+		Expects: []TraceExpect{
+			{Line: 8, TimeMs: 100, State: MOVING_STATE},
+			{Line: 9, TimeMs: 200, State: GUARDING_STATE, Wheels: true},
+		},
+	}
+	if !reflect.DeepEqual(trace, want) {
+		t.Errorf("ReadTrace = %+v, want %+v", trace, want)
+	}
+
+	var buf bytes.Buffer
+	if err := trace.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	again, err := ReadTrace(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again.Ticks, want.Ticks) || again.StartMs != want.StartMs || again.ObstacleCm != want.ObstacleCm || again.EdgeThresholds != want.EdgeThresholds {
+		t.Errorf("Write then ReadTrace = %+v, want %+v", again, want)
+	}
+}
+
+func TestReadTrace_Errors(t *testing.T) {
+	tests := []struct {
+		name, trace, want string
+	}{
+		{"short tick", "0 walk -1 1 2 3", "line 1: want <time>"},
+		{"unknown mode", "0 run -1 1 2 3 4", `line 1: unknown mode "run"`},
+		{"bad distance", "0 walk -5 1 2 3 4", `line 1: bad distance "-5"`},
+		{"bad IR", "0 walk -1 1 2 3 x", "line 1: "},
+		{"time backwards", "100 walk -1 1 2 3 4\n50 walk -1 1 2 3 4", "line 2: time goes backwards"},
+		{"unknown state", "expect 0 dancing", `line 1: unknown state "dancing"`},
+		{"one wheel", "expect 0 moving 50", "line 1: want expect"},
+		{"edge count", "edge 1 2", "line 1: want 4 edge thresholds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadTrace(strings.NewReader(tt.trace))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q...", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplay_MatchesTelemetry(t *testing.T) {
+	// Record a run with telemetry, then replay its sensor readings: the pet must make the same decisions.
+	fr := NewFakeRobot()
+	fr.Robot.Battery = nil
+	p := New(fr.Robot)
+	p.SetTelemetry(true)
+	for i := 0; i < 300; i++ {
+		switch i {
+		case 100:
+			fr.IRSensors[IR_FRONT_RIGHT].Value = 0
+		case 103:
+			fr.IRSensors[IR_FRONT_RIGHT].Value = FAKE_SURFACE_READING
+		case 200:
+			fr.Ultrasonic.Distance = 10
+		}
+		p.Tick()
+	}
+	recorded := frames(t, fr)
+	steps := Replay(TraceFromTelemetry(recorded))
+	if len(steps) != len(recorded) {
+		t.Fatalf("%d steps for %d frames", len(steps), len(recorded))
+	}
+	seen := map[int]bool{}
+	for i, s := range steps {
+		r := recorded[i]
+		if s.State != int(r.State) || s.Left != int(r.LeftMotor) || s.Right != int(r.RightMotor) {
+			t.Fatalf("at %d ms replay did %+v, recorded state %d wheels (%d, %d)", s.TimeMs, s, r.State, r.LeftMotor, r.RightMotor)
+		}
+		seen[s.State] = true
+	}
+	if !seen[EDGE_AVOIDANCE_STATE] || !seen[OBSTACLE_AVOIDANCE_STATE] {
+		t.Errorf("states seen = %v, want both avoidances", seen)
+	}
+}
+
+func TestReplay_SonarFaultFromTelemetry(t *testing.T) {
+	// The sonar fails, then answers again with nothing in range; one IR sensor is missing and one reads 0
+	// throughout. The replay must go in and out of degraded mode and see the sensors as the pet did.
+	fr := NewFakeRobot()
+	fr.Robot.Battery = nil
+	fr.Robot.IRSensors[IR_REAR_RIGHT] = nil
+	fr.IRSensors[IR_REAR_LEFT].Value = 0
+	p := New(fr.Robot)
+	p.SetTelemetry(true)
+	for i := 0; i < 200; i++ {
+		switch i {
+		case 50:
+			fr.Ultrasonic.Status = navlogic.RangeNoEcho
+		case 70:
+			fr.Ultrasonic.Status = navlogic.RangeOK
+			fr.Ultrasonic.Distance = navlogic.TimeoutDistance
+		case 150:
+			fr.IRSensors[IR_FRONT_LEFT].Value = 0
+		case 153:
+			fr.IRSensors[IR_FRONT_LEFT].Value = FAKE_SURFACE_READING
+		}
+		p.Tick()
+	}
+	recorded := frames(t, fr)
+	degraded := func(s navlogic.TelemetrySample) bool { return s.Flags&navlogic.TelemetryFlagSonarDegraded != 0 }
+	if !degraded(recorded[70]) || degraded(recorded[len(recorded)-1]) {
+		t.Fatal("the recorded pet did not go into degraded mode and back")
+	}
+	steps := Replay(TraceFromTelemetry(recorded))
+	for i, s := range steps {
+		r := recorded[i]
+		if s.State != int(r.State) || s.Left != int(r.LeftMotor) || s.Right != int(r.RightMotor) {
+			t.Fatalf("at %d ms replay did %+v, recorded state %d wheels (%d, %d)", s.TimeMs, s, r.State, r.LeftMotor, r.RightMotor)
+		}
+	}
+}
+
+func TestTraceFromTelemetry(t *testing.T) {
+	samples := []navlogic.TelemetrySample{
+		{TimeMs: 100, LoopMs: 10, Distance: 40, IR: [navlogic.TelemetryIRSensors]uint16{1000, 1000, 0, 0}, IRFitted: 0b0111},
+		{TimeMs: 110, Distance: -1, IR: [navlogic.TelemetryIRSensors]uint16{1000, 0, 900, 0}, IRFitted: 0b0111},
+		{TimeMs: 5, Mode: GUARD_MODE}, // the board restarted
+	}
+	trace := TraceFromTelemetry(samples)
+	want := []TraceTick{
+		{TimeMs: 100, Distance: 40, IR: [IR_SENSOR_COUNT]int{1000, 1000, 0, TRACE_NO_SENSOR}},
+		{TimeMs: 110, Distance: -1, IR: [IR_SENSOR_COUNT]int{1000, 0, 900, TRACE_NO_SENSOR}},
+	}
+	if trace.StartMs != 90 {
+		t.Errorf("start = %d ms, want 90", trace.StartMs)
+	}
+	if !reflect.DeepEqual(trace.Ticks, want) {
+		t.Errorf("ticks = %+v, want %+v", trace.Ticks, want)
+	}
+}
+
+func TestTraceFromTelemetry_SonarFaults(t *testing.T) {
+	// Only readings flagged as faults become faults, whether or not the sensor is degraded.
+	const (
+		fault    = navlogic.TelemetryFlagSonarFault
+		degraded = navlogic.TelemetryFlagSonarDegraded
+	)
+	samples := []navlogic.TelemetrySample{
+		{TimeMs: 100, LoopMs: 100, Distance: -1},
+		{TimeMs: 200, Distance: -1, Flags: fault},
+		{TimeMs: 300, Distance: -1, Flags: fault | degraded},
+		{TimeMs: 400, Distance: -1, Flags: degraded},
+		{TimeMs: 500, Distance: 25, Flags: degraded},
+	}
+	var got []int
+	for _, tick := range TraceFromTelemetry(samples).Ticks {
+		got = append(got, tick.Distance)
+	}
+	want := []int{-1, TRACE_SONAR_FAULT, TRACE_SONAR_FAULT, -1, 25}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("distances = %v, want %v", got, want)
+	}
+}
+
+func TestTraceCheck(t *testing.T) {
+	trace, err := ReadTrace(strings.NewReader("start 0\nexpect 100 moving 50 50\nexpect 150 edge\nexpect 900 moving\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := []ReplayStep{
+		{TimeMs: 100, State: MOVING_STATE, Left: 50, Right: 50},
+		{TimeMs: 200, State: MOVING_STATE, Left: 50, Right: 50},
+	}
+	err = trace.Check(steps)
+	if err == nil {
+		t.Fatal("Check passed a trace with unmet expectations")
+	}
+	want := "line 3: at 200 ms got moving 50 50, want edge\nline 4: trace ends before 900 ms"
+	if err.Error() != want {
+		t.Errorf("Check = %q, want %q", err, want)
+	}
+	if err := trace.Check(steps[:1]); err == nil || strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Check = %v, want only the later lines to fail", err)
+	}
+}
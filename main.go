@@ -1,39 +1,24 @@
 package main
 
 import (
-	"machine"
-	"time"
+	"github.com/GyeongHoKim/tiny-pet/internal/logging"
+	"github.com/GyeongHoKim/tiny-pet/internal/pet"
 )
 
-func main() {
-	robot := NewRobot()
+// MAIN_LOOP_MS is the main loop period; navigation runs every pet.NAV_PERIOD_MS within it.
+const MAIN_LOOP_MS = 10
 
-	sensorModule := NewSensorModule(robot.ultraTrig, robot.ultraEcho, &robot.irSensors)
-	motorController := NewMotorController(robot.leftMotor, robot.rightMotor)
-	navigationModule := NewNavigationModule(motorController, sensorModule)
-	behaviorPatterns := NewBehaviorPatterns(robot.statusLed, robot.buzzer)
-	calibrationModule := NewCalibrationModule(robot, sensorModule, motorController)
+// DISPLAY is the OLED panel, chosen with the oled_* build tags (see display_*.go).
+var DISPLAY = pet.DisplayGeometry{Width: DISPLAY_WIDTH, Height: DISPLAY_HEIGHT, Rotation: DISPLAY_ROTATION}
 
-	machine.I2C0.Configure(machine.I2CConfig{Frequency: 400000})
-	displayModule := NewDisplayModule(machine.I2C0)
-
-	robot.Initialize()
-	calibrationModule.CalibrateComplete()
-	navigationModule.SetBehaviorMode(RANDOM_WALK_MODE)
-	displayModule.ShowExpression(EXPR_HAPPY)
+func main() {
+	robot := NewRobot()
+	logging.SetOutput(robot.Serial)
+	p := pet.New(robot)
+	p.Start()
 
-	var lastState int = -1
 	for {
-		navigationModule.Update()
-
-		currentState := navigationModule.GetCurrentState()
-		if currentState != lastState {
-			behaviorPatterns.IndicateStateChange(currentState)
-			displayModule.ShowStateExpression(currentState)
-			lastState = currentState
-		}
-		displayModule.UpdateAnimation()
-
-		time.Sleep(time.Millisecond * 100)
+		p.Tick()
+		p.Rest(MAIN_LOOP_MS)
 	}
 }
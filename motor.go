@@ -0,0 +1,47 @@
+//go:build tinygo
+
+package main
+
+import (
+	"machine"
+)
+
+const MOTOR_PWM_PERIOD = 50000 // ns (20 kHz, above hearing)
+
+// pwmTimer is the PWM API shared by the AVR timers and the STM32 TIMx peripherals.
+type pwmTimer interface {
+	Configure(config machine.PWMConfig) error
+	Channel(pin machine.Pin) (uint8, error)
+	Set(channel uint8, value uint32)
+	Top() uint32
+}
+
+// Motor is a DC motor on the Mini L298N: IN1 carries PWM, IN2 selects direction (sign-magnitude drive).
+type Motor struct {
+	in1     machine.Pin
+	in2     machine.Pin
+	pwm     pwmTimer
+	channel uint8
+}
+
+// NewMotor claims a PWM channel of the already configured timer for in1.
+func NewMotor(pwm pwmTimer, in1, in2 machine.Pin) *Motor {
+	motor := &Motor{in1: in1, in2: in2, pwm: pwm}
+	motor.in2.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	motor.channel, _ = pwm.Channel(in1)
+	motor.SetSpeed(0)
+	return motor
+}
+
+// SetSpeed drives forward with IN2 low and IN1 at speed% duty; reverse holds IN2 high, so IN1's
+// high phase brakes and the duty is inverted.
+func (m *Motor) SetSpeed(speed int) {
+	duty := speed
+	if speed < 0 {
+		m.in2.High()
+		duty = 100 + speed
+	} else {
+		m.in2.Low()
+	}
+	m.pwm.Set(m.channel, m.pwm.Top()*uint32(duty)/100)
+}
@@ -0,0 +1,21 @@
+//go:build tinygo && !bluepill
+
+package main
+
+import (
+	"device/avr"
+	"time"
+)
+
+// IdleSleep waits in the ATmega328P idle sleep mode: the CPU halts until the next interrupt, while the
+// runtime's Timer0 tick, the echo pin interrupt, Timer2 tones and I2C keep running.
+type IdleSleep struct{}
+
+func (IdleSleep) Idle(ms uint32) {
+	deadline := time.Now().Add(time.Duration(ms) * time.Millisecond)
+	avr.SMCR.Set(avr.SMCR_SE) // SM2..0 = 000: idle
+	for time.Now().Before(deadline) {
+		avr.Asm("sleep")
+	}
+	avr.SMCR.Set(0)
+}
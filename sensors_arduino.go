@@ -0,0 +1,11 @@
+//go:build tinygo && !bluepill
+
+package main
+
+// pulseTrigger sends the 10 µs HC-SR04 trigger pulse; AVR sleeps are too coarse, so it spins.
+func (u *Ultrasonic) pulseTrigger() {
+	u.trig.High()
+	for i := 0; i < 160; i++ {
+	}
+	u.trig.Low()
+}
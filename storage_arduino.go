@@ -0,0 +1,66 @@
+//go:build tinygo && !bluepill
+
+package main
+
+import (
+	"device/avr"
+	"errors"
+	"runtime/interrupt"
+)
+
+// EEPROM_SIZE is the ATmega328P data EEPROM size in bytes.
+const EEPROM_SIZE = 1024
+
+var errEEPROMRange = errors.New("eeprom: out of range")
+
+// EEPROM reads and writes the ATmega328P data EEPROM through its control registers.
+type EEPROM struct{}
+
+func (EEPROM) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 || off+int64(len(p)) > EEPROM_SIZE {
+		return 0, errEEPROMRange
+	}
+	for i := range p {
+		p[i] = eepromRead(uint16(off) + uint16(i))
+	}
+	return len(p), nil
+}
+
+// WriteAt only programs bytes that change, saving wear and about 3.4 ms per unchanged byte.
+func (EEPROM) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 || off+int64(len(p)) > EEPROM_SIZE {
+		return 0, errEEPROMRange
+	}
+	for i, b := range p {
+		addr := uint16(off) + uint16(i)
+		if eepromRead(addr) != b {
+			eepromWrite(addr, b)
+		}
+	}
+	return len(p), nil
+}
+
+func eepromWait() {
+	for avr.EECR.HasBits(avr.EECR_EEPE) {
+	}
+}
+
+func eepromRead(addr uint16) byte {
+	eepromWait()
+	avr.EEARH.Set(uint8(addr >> 8))
+	avr.EEARL.Set(uint8(addr))
+	avr.EECR.SetBits(avr.EECR_EERE)
+	return avr.EEDR.Get()
+}
+
+func eepromWrite(addr uint16, value byte) {
+	eepromWait()
+	avr.EEARH.Set(uint8(addr >> 8))
+	avr.EEARL.Set(uint8(addr))
+	avr.EEDR.Set(value)
+	// EEPE must follow EEMPE within four cycles, so nothing may interrupt in between.
+	state := interrupt.Disable()
+	avr.EECR.Set(avr.EECR_EEMPE)
+	avr.EECR.Set(avr.EECR_EEMPE | avr.EECR_EEPE)
+	interrupt.Restore(state)
+}
@@ -0,0 +1,83 @@
+//go:build tinygo && bluepill
+
+package main
+
+import (
+	"device/stm32"
+	"errors"
+	"runtime/volatile"
+	"unsafe"
+)
+
+// CALIBRATION_FLASH_PAGE is the last 1 KB page of the STM32F103C8's 64 KB flash, reserved for
+// the calibration record; the firmware image must stay below it, which `make size-bluepill` checks
+// in CI and before flashing.
+const (
+	CALIBRATION_FLASH_PAGE = 0x0800FC00
+	FLASH_PAGE_SIZE        = 1024
+	flashKey1              = 0x45670123
+	flashKey2              = 0xCDEF89AB
+)
+
+var (
+	errFlashRange = errors.New("flash: out of range")
+	errFlashAlign = errors.New("flash: offset must be halfword aligned")
+	errFlashWrite = errors.New("flash: program or erase failed")
+)
+
+// FlashPage stores data in one flash page, programmed through the flash controller registers.
+type FlashPage struct {
+	addr uintptr
+}
+
+func (f FlashPage) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 || off+int64(len(p)) > FLASH_PAGE_SIZE {
+		return 0, errFlashRange
+	}
+	for i := range p {
+		p[i] = *(*byte)(unsafe.Pointer(f.addr + uintptr(off) + uintptr(i)))
+	}
+	return len(p), nil
+}
+
+// WriteAt erases the whole page before programming p at off, so a record must be written in one call.
+func (f FlashPage) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 || off+int64(len(p)) > FLASH_PAGE_SIZE {
+		return 0, errFlashRange
+	}
+	if off%2 != 0 {
+		return 0, errFlashAlign
+	}
+
+	stm32.FLASH.KEYR.Set(flashKey1)
+	stm32.FLASH.KEYR.Set(flashKey2)
+	defer stm32.FLASH.CR.SetBits(stm32.FLASH_CR_LOCK)
+	stm32.FLASH.SR.Set(stm32.FLASH_SR_EOP | stm32.FLASH_SR_PGERR | stm32.FLASH_SR_WRPRTERR)
+
+	stm32.FLASH.CR.SetBits(stm32.FLASH_CR_PER)
+	stm32.FLASH.AR.Set(uint32(f.addr))
+	stm32.FLASH.CR.SetBits(stm32.FLASH_CR_STRT)
+	flashWait()
+	stm32.FLASH.CR.ClearBits(stm32.FLASH_CR_PER)
+
+	stm32.FLASH.CR.SetBits(stm32.FLASH_CR_PG)
+	for i := 0; i < len(p); i += 2 {
+		halfword := uint16(p[i]) | 0xFF00
+		if i+1 < len(p) {
+			halfword = uint16(p[i]) | uint16(p[i+1])<<8
+		}
+		(*volatile.Register16)(unsafe.Pointer(f.addr + uintptr(off) + uintptr(i))).Set(halfword)
+		flashWait()
+	}
+	stm32.FLASH.CR.ClearBits(stm32.FLASH_CR_PG)
+
+	if stm32.FLASH.SR.HasBits(stm32.FLASH_SR_PGERR | stm32.FLASH_SR_WRPRTERR) {
+		return 0, errFlashWrite
+	}
+	return len(p), nil
+}
+
+func flashWait() {
+	for stm32.FLASH.SR.HasBits(stm32.FLASH_SR_BSY) {
+	}
+}
@@ -0,0 +1,51 @@
+//go:build tinygo
+
+package main
+
+import (
+	"machine"
+	"runtime/interrupt"
+	"time"
+
+	"github.com/GyeongHoKim/tiny-pet/internal/navlogic"
+)
+
+var bootTime = time.Now()
+
+func micros() uint32 {
+	return uint32(time.Since(bootTime) / time.Microsecond)
+}
+
+// Ultrasonic is an HC-SR04 on a trigger/echo pin pair. The echo pulse is timed by a pin-change
+// interrupt on the echo pin, so a ping runs in the background while the main loop keeps going.
+type Ultrasonic struct {
+	trig  machine.Pin
+	echo  machine.Pin
+	timer navlogic.EchoTimer
+}
+
+func NewUltrasonic(trig, echo machine.Pin) *Ultrasonic {
+	trig.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	echo.Configure(machine.PinConfig{Mode: machine.PinInput})
+	u := &Ultrasonic{trig: trig, echo: echo}
+	echo.SetInterrupt(machine.PinToggle, func(pin machine.Pin) {
+		u.timer.Edge(pin.Get(), micros())
+	})
+	u.ReadRange()
+	return u
+}
+
+// ReadRange returns the latest completed ping and sends the next one every navlogic.PingIntervalUs.
+func (u *Ultrasonic) ReadRange() navlogic.RangeReading {
+	now := micros()
+	state := interrupt.Disable()
+	reading, due := u.timer.Poll(now)
+	if due {
+		u.timer.Trigger(now)
+	}
+	interrupt.Restore(state)
+	if due {
+		u.pulseTrigger()
+	}
+	return reading
+}